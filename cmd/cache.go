@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/devusSs/dropawp/internal/cache"
+	"github.com/devusSs/dropawp/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect or clear the local price cache.",
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete all cached prices.",
+	Run: func(_ *cobra.Command, _ []string) {
+		err := cache.Clear()
+		cobra.CheckErr(err)
+
+		fmt.Println("Price cache has been cleared.")
+	},
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show statistics about the cached prices.",
+	Run: func(_ *cobra.Command, _ []string) {
+		ttl := config.DefaultPriceCacheTTL
+
+		c, err := config.Read()
+		if err == nil {
+			ttl = c.PriceCacheDuration()
+		}
+
+		var summary *cache.Summary
+		summary, err = cache.Summarize(ttl)
+		cobra.CheckErr(err)
+
+		err = printCacheSummary(summary, ttl)
+		cobra.CheckErr(err)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+
+	cacheCmd.AddCommand(cacheClearCmd)
+	cacheCmd.AddCommand(cacheStatsCmd)
+}
+
+func printCacheSummary(summary *cache.Summary, ttl time.Duration) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, tabwriterPadding, ' ', 0)
+
+	rows := [][2]string{
+		{"Cache File", summary.Path},
+		{"TTL", ttl.String()},
+		{"Entries", strconv.Itoa(summary.Entries)},
+		{"Fresh", strconv.Itoa(summary.Fresh)},
+		{"Expired", strconv.Itoa(summary.Expired)},
+	}
+
+	if summary.Entries > 0 {
+		rows = append(rows,
+			[2]string{"Oldest Entry", summary.Oldest.Format(time.RFC3339)},
+			[2]string{"Newest Entry", summary.Newest.Format(time.RFC3339)},
+		)
+	}
+
+	for _, row := range rows {
+		_, err := fmt.Fprintf(w, "%s\t%s\n", row[0], row[1])
+		if err != nil {
+			return fmt.Errorf("failed to write cache summary: %w", err)
+		}
+	}
+
+	providers := make([]string, 0, len(summary.PerProvider))
+	for provider := range summary.PerProvider {
+		providers = append(providers, provider)
+	}
+
+	sort.Strings(providers)
+
+	for _, provider := range providers {
+		_, err := fmt.Fprintf(w, "Provider %s\t%d\n", provider, summary.PerProvider[provider])
+		if err != nil {
+			return fmt.Errorf("failed to write cache summary: %w", err)
+		}
+	}
+
+	return w.Flush()
+}
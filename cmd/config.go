@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"text/tabwriter"
@@ -11,7 +15,9 @@ import (
 
 	"github.com/devusSs/dropawp/internal/config"
 	"github.com/devusSs/dropawp/internal/secret"
+	"github.com/devusSs/dropawp/internal/steam"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var cfg *config.Config
@@ -19,47 +25,51 @@ var cfg *config.Config
 var (
 	configCmdShowExtended  bool
 	configCmdShowSensitive bool
+	configCmdReveal        bool
+	configCmdJSON          bool
 )
 
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Show, edit or delete the configuration.",
-	PersistentPreRun: func(_ *cobra.Command, _ []string) {
+	PersistentPreRun: func(cmd *cobra.Command, _ []string) {
 		var err error
-		cfg, err = config.Read()
+		if cmd == configEditCmd {
+			// Overrides only apply to the current run and must not be written back.
+			cfg, err = config.ReadStored()
+		} else {
+			cfg, err = config.Read()
+		}
+		cobra.CheckErr(err)
+
+		err = useSecretStore(cfg)
 		cobra.CheckErr(err)
 	},
 	Run: func(_ *cobra.Command, _ []string) {
-		err := printConfigAsTable(configCmdShowExtended)
-		cobra.CheckErr(err)
-
-		if configCmdShowSensitive {
-			fmt.Println()
-
-			w := tabwriter.NewWriter(os.Stdout, 0, 0, tabwriterPadding, ' ', 0)
-			_, err = fmt.Fprintln(w, "Secret Name\tSecret Value")
+		if configCmdJSON {
+			err := printConfigAsJSON()
 			cobra.CheckErr(err)
 
-			_, err = fmt.Fprintln(w, "-----------\t------------")
-			cobra.CheckErr(err)
-
-			var csFloatAPIKey string
-			csFloatAPIKey, err = getSecret(secret.CSFloatAPIKey)
-			cobra.CheckErr(err)
+			return
+		}
 
-			_, err = fmt.Fprintln(w, "CSFloat API Key\t"+csFloatAPIKey)
-			cobra.CheckErr(err)
+		err := printConfigAsTable(configCmdShowExtended)
+		cobra.CheckErr(err)
 
-			if !cfg.SkipSteamServicesCheck || !cfg.SkipSteamUserCheck {
-				var steamAPIKey string
-				steamAPIKey, err = getSecret(secret.SteamAPIKey)
+		if configCmdShowSensitive || configCmdReveal {
+			if configCmdReveal {
+				var confirmed bool
+				confirmed, err = confirm("This prints the full secret values, make sure nobody is watching your screen. Continue?")
 				cobra.CheckErr(err)
 
-				_, err = fmt.Fprintln(w, "Steam API Key\t"+steamAPIKey)
-				cobra.CheckErr(err)
+				if !confirmed {
+					cobra.CheckErr("Aborted, secrets have not been revealed.")
+				}
 			}
 
-			err = w.Flush()
+			fmt.Println()
+
+			err = printSecretsTable(configCmdReveal)
 			cobra.CheckErr(err)
 		}
 	},
@@ -75,7 +85,7 @@ var configDeleteCmd = &cobra.Command{
 			err := secret.DeleteAll()
 			cobra.CheckErr(err)
 
-			fmt.Println("All secrets have been deleted.")
+			fmt.Printf("All secrets of project %s have been deleted, global secrets are kept.\n", cfg.ProjectName)
 		}
 
 		err := config.Delete(cfg)
@@ -89,10 +99,29 @@ var (
 	configEditProjectName        string
 	configEditCooldown           string
 	configEditSteamID64          string
+	configEditAddAccounts        []string
+	configEditRemoveAccounts     []string
 	configEditItemsFile          string
+	configEditClearItemsFile     bool
 	configEditSkipSteamServices  string
+	configEditRequireServices    []string
 	configEditSkipSteamUser      string
+	configEditTradeBanAction     string
 	configEditSkipFilterItems    string
+	configEditIncludeTradeHeld   string
+	configEditCurrency           string
+	configEditExchangeProvider   string
+	configEditExchangeSource     string
+	configEditFeeProvider        string
+	configEditAppID              int
+	configEditContextID          string
+	configEditInventorySource    string
+	configEditPricingProvider    string
+	configEditPriceCacheTTL      string
+	configEditArchiveRaw         string
+	configEditSecretBackend      string
+	configEditSecretFile         string
+	configEditSecretCommand      string
 	configEditUpdateSecretKeys   []string
 	configEditUpdateSecretValues []string
 )
@@ -117,18 +146,53 @@ var configEditCmd = &cobra.Command{
 		}
 
 		if configEditSteamID64 != "" {
-			steamID, err := parseUint64(configEditSteamID64)
+			steamID, err := resolveSteamID(configEditSteamID64)
 			cobra.CheckErr(err)
 
 			cfg.SteamID64 = steamID
 			updated = true
 		}
 
+		for _, account := range configEditAddAccounts {
+			label, input, ok := strings.Cut(account, "=")
+			if !ok {
+				cobra.CheckErr(fmt.Sprintf("invalid account %q, expected label=steam-id", account))
+			}
+
+			steamID, err := resolveSteamID(input)
+			cobra.CheckErr(err)
+
+			cfg.Accounts = append(cfg.Accounts, config.Account{Label: label, SteamID64: steamID})
+			updated = true
+		}
+
+		for _, label := range configEditRemoveAccounts {
+			removed := false
+			for i, account := range cfg.Accounts {
+				if account.Label == label {
+					cfg.Accounts = append(cfg.Accounts[:i], cfg.Accounts[i+1:]...)
+					removed = true
+					break
+				}
+			}
+
+			if !removed {
+				cobra.CheckErr(fmt.Sprintf("account %q does not exist", label))
+			}
+
+			updated = true
+		}
+
 		if configEditItemsFile != "" {
 			cfg.AdditionalItemsFile = configEditItemsFile
 			updated = true
 		}
 
+		if configEditClearItemsFile {
+			cfg.AdditionalItemsFile = ""
+			updated = true
+		}
+
 		if configEditSkipSteamServices != "" {
 			skip, err := parseBool(configEditSkipSteamServices)
 			cobra.CheckErr(err)
@@ -137,6 +201,20 @@ var configEditCmd = &cobra.Command{
 			updated = true
 		}
 
+		for _, requirement := range configEditRequireServices {
+			service, state, ok := strings.Cut(requirement, "=")
+			if !ok {
+				cobra.CheckErr(fmt.Sprintf("invalid service requirement %q, expected service=state", requirement))
+			}
+
+			if cfg.RequiredSteamServices == nil {
+				cfg.RequiredSteamServices = config.DefaultRequiredSteamServices()
+			}
+
+			cfg.RequiredSteamServices[service] = state
+			updated = true
+		}
+
 		if configEditSkipSteamUser != "" {
 			skip, err := parseBool(configEditSkipSteamUser)
 			cobra.CheckErr(err)
@@ -145,6 +223,11 @@ var configEditCmd = &cobra.Command{
 			updated = true
 		}
 
+		if configEditTradeBanAction != "" {
+			cfg.TradeBanAction = configEditTradeBanAction
+			updated = true
+		}
+
 		if configEditSkipFilterItems != "" {
 			skip, err := parseBool(configEditSkipFilterItems)
 			cobra.CheckErr(err)
@@ -153,6 +236,77 @@ var configEditCmd = &cobra.Command{
 			updated = true
 		}
 
+		if configEditIncludeTradeHeld != "" {
+			include, err := parseBool(configEditIncludeTradeHeld)
+			cobra.CheckErr(err)
+
+			cfg.IncludeTradeHeldItems = include
+			updated = true
+		}
+
+		if configEditCurrency != "" {
+			cfg.Currency = strings.ToUpper(configEditCurrency)
+			updated = true
+		}
+
+		if configEditExchangeProvider != "" {
+			cfg.ExchangeRateProvider = configEditExchangeProvider
+			updated = true
+		}
+
+		if configEditExchangeSource != "" {
+			cfg.ExchangeRateSource = configEditExchangeSource
+			updated = true
+		}
+
+		if configEditFeeProvider != "" {
+			cfg.FeeProvider = configEditFeeProvider
+			updated = true
+		}
+
+		if configEditAppID != 0 {
+			cfg.AppID = configEditAppID
+			updated = true
+		}
+
+		if configEditContextID != "" {
+			cfg.ContextID = configEditContextID
+			updated = true
+		}
+
+		if configEditInventorySource != "" {
+			cfg.InventorySource = configEditInventorySource
+			updated = true
+		}
+
+		if configEditPricingProvider != "" {
+			cfg.PricingProvider = configEditPricingProvider
+			updated = true
+		}
+
+		if configEditPriceCacheTTL != "" {
+			duration, err := parseExtendedDuration(configEditPriceCacheTTL)
+			cobra.CheckErr(err)
+
+			cfg.PriceCacheTTL = duration
+			updated = true
+		}
+
+		if configEditArchiveRaw != "" {
+			archiveRaw, err := parseBool(configEditArchiveRaw)
+			cobra.CheckErr(err)
+
+			cfg.ArchiveRawResponses = archiveRaw
+			updated = true
+		}
+
+		if configEditSecretBackend != "" {
+			cfg.SecretBackend = configEditSecretBackend
+			cfg.SecretFile = configEditSecretFile
+			cfg.SecretCommand = configEditSecretCommand
+			updated = true
+		}
+
 		if !updated {
 			cobra.CheckErr("No changes specified. Use --help to see available flags.")
 		}
@@ -166,6 +320,98 @@ var configEditCmd = &cobra.Command{
 	},
 }
 
+var configShowOrigin bool
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show every configuration value, optionally with the layer it was taken from.",
+	Run: func(_ *cobra.Command, _ []string) {
+		fields, err := cfg.Fields()
+		cobra.CheckErr(err)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, tabwriterPadding, ' ', 0)
+
+		if configShowOrigin {
+			_, err = fmt.Fprintf(w, "Field\tValue\tOrigin\n")
+			cobra.CheckErr(err)
+
+			_, err = fmt.Fprintf(w, "-----\t-----\t------\n")
+			cobra.CheckErr(err)
+		} else {
+			_, err = fmt.Fprintf(w, "Field\tValue\n")
+			cobra.CheckErr(err)
+
+			_, err = fmt.Fprintf(w, "-----\t-----\n")
+			cobra.CheckErr(err)
+		}
+
+		for _, field := range fields {
+			if configShowOrigin {
+				_, err = fmt.Fprintf(w, "%s\t%s\t%s\n", field.Name, field.Value, formatOrigin(field))
+			} else {
+				_, err = fmt.Fprintf(w, "%s\t%s\n", field.Name, field.Value)
+			}
+			cobra.CheckErr(err)
+		}
+
+		err = w.Flush()
+		cobra.CheckErr(err)
+	},
+}
+
+// formatOrigin names the layer of a value, environment overrides include the variable name.
+func formatOrigin(field config.Field) string {
+	switch field.Origin {
+	case config.OriginEnv:
+		return fmt.Sprintf("%s (%s)", field.Origin, config.EnvName(field.Name))
+	case config.OriginFlag:
+		return field.Origin + " (--set)"
+	default:
+		return field.Origin
+	}
+}
+
+var (
+	configExportFormat string
+	configExportOutput string
+)
+
+var configExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the configuration as JSON, YAML or TOML, secrets are never included.",
+	Run: func(_ *cobra.Command, _ []string) {
+		format := configExportFormat
+		if format == "" && configExportOutput != "" {
+			var err error
+			format, err = config.FormatFromPath(configExportOutput)
+			cobra.CheckErr(err)
+		}
+
+		if format == "" {
+			format = config.FormatJSON
+		}
+
+		if !slices.Contains(config.Formats(), format) {
+			cobra.CheckErr(fmt.Errorf("unknown format '%s', expected one of %s", format, strings.Join(config.Formats(), ", ")))
+		}
+
+		data, err := config.Encode(cfg, format)
+		cobra.CheckErr(err)
+
+		if configExportOutput == "" {
+			_, err = os.Stdout.Write(data)
+			cobra.CheckErr(err)
+
+			return
+		}
+
+		err = os.WriteFile(configExportOutput, data, 0600)
+		cobra.CheckErr(err)
+
+		fmt.Printf("Configuration exported to %s.\n", configExportOutput)
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(configCmd)
 
@@ -173,7 +419,15 @@ func init() {
 		BoolVar(&configCmdShowExtended, "extended", false,
 			"Show extended configuration values")
 	configCmd.Flags().
-		BoolVar(&configCmdShowSensitive, "show-sensitive", false, "Show sensitive configuration values")
+		BoolVar(&configCmdShowSensitive, "show-sensitive", false,
+			"Show masked secrets with their fingerprint")
+	configCmd.Flags().
+		BoolVar(&configCmdReveal, "reveal", false, "Show the full secret values after confirmation")
+	configCmd.Flags().
+		BoolVar(&configCmdJSON, "json", false, "Print the configuration as JSON, secrets are never included")
+
+	configCmd.MarkFlagsMutuallyExclusive("json", "show-sensitive")
+	configCmd.MarkFlagsMutuallyExclusive("json", "reveal")
 
 	configCmd.AddCommand(configDeleteCmd)
 
@@ -181,20 +435,81 @@ func init() {
 		BoolVar(&configDeleteCmdSecrets, "secrets", false,
 			"Delete secrets in addition to the configuration file")
 
+	configCmd.AddCommand(configShowCmd)
+
+	configShowCmd.Flags().
+		BoolVar(&configShowOrigin, "origin", false, "Show whether a value comes from the config file, environment or a flag")
+
+	configCmd.AddCommand(configExportCmd)
+
+	configExportCmd.Flags().
+		StringVar(&configExportFormat, "format", "",
+			"Export format (json, yaml or toml), detected by the output file extension by default")
+	configExportCmd.Flags().
+		StringVarP(&configExportOutput, "output", "o", "", "Write the configuration to a file instead of stdout")
+
 	configCmd.AddCommand(configEditCmd)
 
 	configEditCmd.Flags().StringVar(&configEditProjectName, "project-name", "", "Set project name")
 	configEditCmd.Flags().
 		StringVar(&configEditCooldown, "cooldown", "", "Set cooldown duration (e.g., 30m, 1h, 2d)")
-	configEditCmd.Flags().StringVar(&configEditSteamID64, "steam-id", "", "Set Steam ID64")
+	configEditCmd.Flags().StringVar(&configEditSteamID64, "steam-id", "", "Set Steam ID64, profile URL or vanity name")
+	configEditCmd.Flags().
+		StringSliceVar(&configEditAddAccounts, "add-account", nil,
+			"Add an account as label=steam-id (Steam ID64, profile URL or vanity name)")
+	configEditCmd.Flags().
+		StringSliceVar(&configEditRemoveAccounts, "remove-account", nil, "Remove an account by label")
 	configEditCmd.Flags().
 		StringVar(&configEditItemsFile, "items-file", "", "Set additional items file path")
+	configEditCmd.Flags().
+		BoolVar(&configEditClearItemsFile, "clear-items-file", false, "Stop tracking the additional items file")
 	configEditCmd.Flags().
 		StringVar(&configEditSkipSteamServices, "skip-steam-services", "", "Skip Steam services check (true/false)")
+	configEditCmd.Flags().
+		StringSliceVar(&configEditRequireServices, "require-service", nil,
+			"Set minimum state of a Steam service as service=state (e.g., SteamCommunity=normal)")
 	configEditCmd.Flags().
 		StringVar(&configEditSkipSteamUser, "skip-user-check", "", "Skip Steam user check (true/false)")
+	configEditCmd.Flags().
+		StringVar(&configEditTradeBanAction, "trade-ban-action", "",
+			"Set action if an account is trade banned (warn/zero_liquidation/abort)")
 	configEditCmd.Flags().
 		StringVar(&configEditSkipFilterItems, "skip-filter-untradable", "", "Skip filter untradable items (true/false)")
+	configEditCmd.Flags().
+		StringVar(&configEditIncludeTradeHeld, "include-trade-held", "",
+			"Include items under a trade cooldown (true/false)")
+	configEditCmd.Flags().
+		StringVar(&configEditCurrency, "currency", "", "Set display currency (e.g., USD, EUR)")
+	configEditCmd.Flags().
+		StringVar(&configEditExchangeProvider, "exchange-rate-provider", "", "Set exchange rate provider (static/ecb)")
+	configEditCmd.Flags().
+		StringVar(&configEditExchangeSource, "exchange-rate-source", "",
+			"Set exchange rate source (rates file for static, URL or file for ecb)")
+	configEditCmd.Flags().
+		StringVar(&configEditFeeProvider, "fee-provider", "", "Set marketplace to calculate fees for (steam/csfloat)")
+	configEditCmd.Flags().
+		IntVar(&configEditAppID, "app-id", 0, "Set Steam app id to track (e.g., 730 for CS2, 440 for TF2)")
+	configEditCmd.Flags().
+		StringVar(&configEditContextID, "context-id", "", "Set Steam inventory context id (e.g., 2)")
+	configEditCmd.Flags().
+		StringVar(&configEditInventorySource, "inventory-source", "",
+			"Set inventory source (community with Web API fallback, or api)")
+	configEditCmd.Flags().
+		StringVar(&configEditPricingProvider, "pricing-provider", "", "Set marketplace to take prices from (csfloat/steam)")
+	configEditCmd.Flags().
+		StringVar(&configEditPriceCacheTTL, "price-cache-ttl", "", "Set price cache TTL (e.g., 30m, 1h, 1d)")
+	configEditCmd.Flags().
+		StringVar(&configEditArchiveRaw, "archive-raw", "",
+			"Archive raw inventory and pricing responses next to snapshots (true/false)")
+	configEditCmd.Flags().
+		StringVar(&configEditSecretBackend, "secret-backend", "",
+			"Set secret backend (keyring/env/file/command), existing secrets are not migrated")
+	configEditCmd.Flags().
+		StringVar(&configEditSecretFile, "secret-file", "",
+			"Set path to the encrypted secrets file of the file backend")
+	configEditCmd.Flags().
+		StringVar(&configEditSecretCommand, "secret-command", "",
+			"Set command of the command backend, {key} is replaced with the secret key (e.g., 'pass show dropawp/{key}')")
 	configEditCmd.Flags().
 		StringSliceVar(&configEditUpdateSecretKeys, "update-secret-keys", nil,
 			"Keys of secrets to update")
@@ -202,6 +517,7 @@ func init() {
 		StringSliceVar(&configEditUpdateSecretValues, "update-secret-values", nil,
 			"Values of secrets to update (must match the keys in --update-secret-keys)")
 	configEditCmd.MarkFlagsRequiredTogether("update-secret-keys", "update-secret-values")
+	configEditCmd.MarkFlagsMutuallyExclusive("items-file", "clear-items-file")
 }
 
 const tabwriterPadding = 2
@@ -223,7 +539,7 @@ func printConfigAsTable(extended bool) error {
 func printBasicConfigTable(w *tabwriter.Writer) error {
 	_, err := fmt.Fprintln(
 		w,
-		"Project Name\tCreated At\tSteam ID 64\tSkip Filter Untradable Items",
+		"Project Name\tCreated At\tSteam ID 64\tSkip Filter Untradable Items\tCurrency",
 	)
 	if err != nil {
 		return fmt.Errorf("failed to write header: %w", err)
@@ -231,17 +547,18 @@ func printBasicConfigTable(w *tabwriter.Writer) error {
 
 	_, err = fmt.Fprintln(
 		w,
-		"------------\t----------\t------------\t--------------------------",
+		"------------\t----------\t------------\t--------------------------\t--------",
 	)
 	if err != nil {
 		return fmt.Errorf("failed to write separator: %w", err)
 	}
 
-	_, err = fmt.Fprintf(w, "%s\t%s\t%d\t%v\n",
+	_, err = fmt.Fprintf(w, "%s\t%s\t%d\t%v\t%s\n",
 		cfg.ProjectName,
 		cfg.CreatedAt.Format(time.RFC3339),
 		cfg.SteamID64,
 		cfg.SkipFilterUntradableItems,
+		cfg.DisplayCurrency(),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to write config values: %w", err)
@@ -251,9 +568,11 @@ func printBasicConfigTable(w *tabwriter.Writer) error {
 }
 
 func printExtendedConfigTable(w *tabwriter.Writer) error {
+	appID, contextID := cfg.SteamApp()
+
 	_, err := fmt.Fprintln(
 		w,
-		"Project Name\tCreated At\tUpdated At\tCooldown Duration\tSkip Steam Services Check\tSteam ID 64\tSkip Steam User Check\tSkip Filter Untradable Items\tAdditional Items File",
+		"Project Name\tCreated At\tUpdated At\tCooldown Duration\tSkip Steam Services Check\tSteam ID 64\tAccounts\tSkip Steam User Check\tSkip Filter Untradable Items\tAdditional Items File\tInclude Trade Held Items\tCurrency\tExchange Rate Provider\tExchange Rate Source\tApp ID\tContext ID\tInventory Source\tPricing Provider\tFee Provider\tArchive Raw Responses",
 	)
 	if err != nil {
 		return fmt.Errorf("failed to write header: %w", err)
@@ -261,22 +580,33 @@ func printExtendedConfigTable(w *tabwriter.Writer) error {
 
 	_, err = fmt.Fprintln(
 		w,
-		"------------\t----------\t----------\t------------------\t---------------------------\t------------\t----------------------\t--------------------------\t----------------------",
+		"------------\t----------\t----------\t------------------\t---------------------------\t------------\t--------\t----------------------\t--------------------------\t----------------------\t------------------------\t--------\t----------------------\t--------------------\t------\t----------\t----------------\t----------------\t------------\t---------------------",
 	)
 	if err != nil {
 		return fmt.Errorf("failed to write separator: %w", err)
 	}
 
-	_, err = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%v\t%d\t%v\t%v\t%s\n",
+	_, err = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%v\t%d\t%s\t%v\t%v\t%s\t%v\t%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\t%v\n",
 		cfg.ProjectName,
 		cfg.CreatedAt.Format(time.RFC3339),
 		cfg.UpdatedAt.Format(time.RFC3339),
 		cfg.CooldownDuration,
 		cfg.SkipSteamServicesCheck,
 		cfg.SteamID64,
+		formatAccounts(cfg.Accounts),
 		cfg.SkipSteamUserCheck,
 		cfg.SkipFilterUntradableItems,
 		cfg.AdditionalItemsFile,
+		cfg.IncludeTradeHeldItems,
+		cfg.DisplayCurrency(),
+		cfg.ExchangeRateProvider,
+		cfg.ExchangeRateSource,
+		appID,
+		contextID,
+		cfg.InventorySourceOrDefault(),
+		pricingProviderName(),
+		feeProviderName(),
+		cfg.ArchiveRawResponses,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to write config values: %w", err)
@@ -285,6 +615,143 @@ func printExtendedConfigTable(w *tabwriter.Writer) error {
 	return w.Flush()
 }
 
+// printSecretsTable prints the secrets used by the configuration, masked unless reveal is set.
+func printSecretsTable(reveal bool) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, tabwriterPadding, ' ', 0)
+
+	_, err := fmt.Fprintln(w, "Secret Name\tSecret Value\tFingerprint")
+	if err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	_, err = fmt.Fprintln(w, "-----------\t------------\t-----------")
+	if err != nil {
+		return fmt.Errorf("failed to write separator: %w", err)
+	}
+
+	keys := []secret.Key{secret.CSFloatAPIKey}
+	if !cfg.SkipSteamServicesCheck || !cfg.SkipSteamUserCheck {
+		keys = append(keys, secret.SteamAPIKey)
+	}
+
+	for _, key := range keys {
+		var value string
+		value, err = getSecret(key)
+		if err != nil {
+			return err
+		}
+
+		shown := secret.Mask(value)
+		if reveal {
+			shown = value
+		}
+
+		_, err = fmt.Fprintf(w, "%s\t%s\t%s\n", key, shown, secret.Fingerprint(value))
+		if err != nil {
+			return fmt.Errorf("failed to write secret: %w", err)
+		}
+	}
+
+	return w.Flush()
+}
+
+type configSecretStatus struct {
+	Name          string    `json:"name"`
+	Set           bool      `json:"set"`
+	LastValidated time.Time `json:"last_validated"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// printConfigAsJSON prints the configuration and which secrets are set, but never their values.
+func printConfigAsJSON() error {
+	secrets := make([]configSecretStatus, 0, len(secret.Keys()))
+	for _, key := range secret.Keys() {
+		status := configSecretStatus{Name: string(key)}
+
+		exists, err := secret.Exists(key)
+		if err != nil {
+			// The backend may not be usable in this environment, which should not prevent printing the config.
+			status.Error = err.Error()
+		}
+
+		status.Set = exists
+
+		status.LastValidated, err = secret.LastValidated(key)
+		if err != nil {
+			return err
+		}
+
+		secrets = append(secrets, status)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	err := enc.Encode(struct {
+		*config.Config
+
+		SecretBackendName string               `json:"secret_backend_name"`
+		Secrets           []configSecretStatus `json:"secrets"`
+	}{
+		Config:            cfg,
+		SecretBackendName: secret.Current().Name(),
+		Secrets:           secrets,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	return nil
+}
+
+// confirm asks a yes/no question on the terminal, anything but yes is treated as no.
+func confirm(question string) (bool, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false, errors.New("confirmation requires an interactive terminal")
+	}
+
+	fmt.Print(question + " [y/N]: ")
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false, fmt.Errorf("failed to read answer: %w", err)
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes", nil
+}
+
+func formatAccounts(accounts []config.Account) string {
+	if len(accounts) == 0 {
+		return "-"
+	}
+
+	formatted := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		formatted = append(formatted, fmt.Sprintf("%s=%d", account.Label, account.SteamID64))
+	}
+
+	return strings.Join(formatted, ",")
+}
+
+// useSecretStore selects the secret backend configured in c and the secret namespace of its project.
+func useSecretStore(c *config.Config) error {
+	store, err := secret.NewStore(c.SecretBackend, secret.Options{
+		File:           c.SecretFile,
+		Command:        c.SecretCommand,
+		CommandTimeout: c.SecretCommandTimeout,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create secret store: %w", err)
+	}
+
+	secret.Use(store)
+	secret.UseNamespace(c.ProjectName)
+
+	return nil
+}
+
 func getSecret(key secret.Key) (string, error) {
 	value, err := secret.Load(key)
 	if err != nil {
@@ -294,6 +761,20 @@ func getSecret(key secret.Key) (string, error) {
 	return value, nil
 }
 
+// optionalSecret returns an empty string if the secret is not set.
+func optionalSecret(key secret.Key) (string, error) {
+	exists, err := secret.Exists(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to check secret %q: %w", key, err)
+	}
+
+	if !exists {
+		return "", nil
+	}
+
+	return getSecret(key)
+}
+
 func parseBool(s string) (bool, error) {
 	if s == "" {
 		return false, errors.New("missing bool value")
@@ -301,11 +782,50 @@ func parseBool(s string) (bool, error) {
 	return strconv.ParseBool(s)
 }
 
-func parseUint64(s string) (uint64, error) {
-	if s == "" {
-		return 0, errors.New("missing uint64 value")
+const resolveSteamIDTimeout = 10 * time.Second
+
+// resolveSteamID accepts a SteamID64, SteamID2, SteamID3, profile URL or vanity name.
+// Vanity names are resolved via the Steam Web API which requires the Steam API key.
+func resolveSteamID(input string) (uint64, error) {
+	return resolveSteamIDWith(input, func() (string, error) {
+		err := checkOrInsertSecret(secret.SteamAPIKey, false)
+		if err != nil {
+			return "", err
+		}
+
+		return getSecret(secret.SteamAPIKey)
+	})
+}
+
+// resolveSteamIDWith resolves vanity names with the Steam API key returned by apiKeyFunc,
+// which is only called if needed.
+func resolveSteamIDWith(input string, apiKeyFunc func() (string, error)) (uint64, error) {
+	id, vanity, err := steam.ParseSteamID(input)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse steam id: %w", err)
 	}
-	return strconv.ParseUint(s, 10, 64)
+
+	if vanity == "" {
+		return id, nil
+	}
+
+	var apiKey string
+	apiKey, err = apiKeyFunc()
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), resolveSteamIDTimeout)
+	defer cancel()
+
+	id, err = steam.ResolveVanityURL(ctx, apiKey, vanity)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve vanity name %s: %w", vanity, err)
+	}
+
+	fmt.Printf("Resolved %s to SteamID64 %d\n", vanity, id)
+
+	return id, nil
 }
 
 const (
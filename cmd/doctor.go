@@ -0,0 +1,470 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"text/tabwriter"
+	"time"
+
+	"github.com/devusSs/dropawp/internal/additional"
+	"github.com/devusSs/dropawp/internal/config"
+	"github.com/devusSs/dropawp/internal/csfloat"
+	"github.com/devusSs/dropawp/internal/secret"
+	"github.com/devusSs/dropawp/internal/steam"
+	"github.com/devusSs/dropawp/internal/system"
+	"github.com/spf13/cobra"
+)
+
+var doctorOffline bool
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the setup and print hints on how to fix problems.",
+	Long: `Diagnose the setup and print hints on how to fix problems.
+
+Checks platform support, the data directory, the secret backend, secrets, the configuration,
+the additional items file, Steam profiles, inventories and CSFloat API reachability.
+Exits with an error if any check failed.`,
+	// The platform check is part of the report instead of aborting.
+	PersistentPreRun: func(_ *cobra.Command, _ []string) {},
+	Run: func(_ *cobra.Command, _ []string) {
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		d := &doctor{ctx: ctx, offline: doctorOffline}
+		d.run()
+
+		err := d.print()
+		cobra.CheckErr(err)
+
+		if d.failed() > 0 {
+			cobra.CheckErr(fmt.Errorf("%d checks failed", d.failed()))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().
+		BoolVar(&doctorOffline, "offline", false, "Skip checks that need network access")
+}
+
+// Statuses of doctor checks.
+const (
+	doctorPass = "pass"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+	doctorSkip = "skip"
+)
+
+const doctorCheckTimeout = 15 * time.Second
+
+// doctorResult is the outcome of a single check. Hint explains how to fix a warning or failure.
+type doctorResult struct {
+	Check   string
+	Status  string
+	Details string
+	Hint    string
+}
+
+func (r doctorResult) String() string {
+	return fmt.Sprintf("doctorResult{Check: %s, Status: %s, Details: %s, Hint: %s}", r.Check, r.Status, r.Details, r.Hint)
+}
+
+type doctor struct {
+	ctx     context.Context
+	offline bool
+	results []doctorResult
+
+	// Set by earlier checks, later checks are skipped without them.
+	config    *config.Config
+	secretsOK bool
+	steamKey  string
+	floatKey  string
+}
+
+func (d *doctor) add(check string, status string, details string, hint string) {
+	d.results = append(d.results, doctorResult{Check: check, Status: status, Details: details, Hint: hint})
+}
+
+func (d *doctor) failed() int {
+	failed := 0
+	for _, r := range d.results {
+		if r.Status == doctorFail {
+			failed++
+		}
+	}
+
+	return failed
+}
+
+func (d *doctor) run() {
+	d.checkPlatform()
+	d.checkDataDir()
+	d.checkConfig()
+	d.checkSecretBackend()
+	d.checkSecrets()
+	d.checkAdditionalItems()
+	d.checkSteamProfiles()
+	d.checkInventories()
+	d.checkCSFloat()
+}
+
+func (d *doctor) checkPlatform() {
+	err := system.CheckSupported()
+	if err != nil {
+		d.add("Platform", doctorFail, err.Error(), "Use one of the supported platforms or build the app yourself.")
+		return
+	}
+
+	d.add("Platform", doctorPass, runtime.GOOS+"/"+runtime.GOARCH, "")
+}
+
+func (d *doctor) checkDataDir() {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		d.add("Data directory", doctorFail, err.Error(), "Make sure the HOME (or USERPROFILE) environment variable is set.")
+		return
+	}
+
+	dir := filepath.Join(home, ".dropawp")
+
+	var info os.FileInfo
+	info, err = os.Stat(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		d.add("Data directory", doctorWarn, dir+" does not exist yet", "Run dropawp init to create it.")
+		return
+	}
+
+	if err != nil {
+		d.add("Data directory", doctorFail, err.Error(), "Check the permissions of "+dir+".")
+		return
+	}
+
+	var probe *os.File
+	probe, err = os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		d.add("Data directory", doctorFail, dir+" is not writable: "+err.Error(),
+			"Make sure your user owns "+dir+", e.g. chown -R $USER "+dir+".")
+		return
+	}
+
+	_ = probe.Close()
+	_ = os.Remove(probe.Name())
+
+	// Permissions are not meaningful on Windows.
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		d.add("Data directory", doctorWarn, fmt.Sprintf("%s is accessible by other users (%s)", dir, info.Mode().Perm()),
+			"Restrict access with chmod 700 "+dir+".")
+		return
+	}
+
+	d.add("Data directory", doctorPass, dir, "")
+}
+
+func (d *doctor) checkConfig() {
+	c, err := config.Read()
+	if err != nil {
+		d.add("Config", doctorFail, err.Error(),
+			"Run dropawp init to create a config or fix the value, dropawp config show --origin shows where it comes from.")
+		return
+	}
+
+	d.config = c
+	cfg = c
+
+	d.add("Config", doctorPass, fmt.Sprintf("project %s (%s)", c.ProjectName, c.FilePath()), "")
+}
+
+func (d *doctor) checkSecretBackend() {
+	c := d.config
+	if c == nil {
+		c = &config.Config{}
+	}
+
+	err := useSecretStore(c)
+	if err != nil {
+		d.add("Secret backend", doctorFail, err.Error(), "Fix secret_backend in the config.")
+		return
+	}
+
+	backend := secret.Current().Name()
+
+	_, err = secret.Exists(secret.CSFloatAPIKey)
+	if err != nil {
+		hint := "Check the secret backend configuration."
+		switch backend {
+		case secret.BackendKeyring:
+			hint = "Set up a system password store (see the keyring section of the README) or use --secret-backend file."
+		case secret.BackendFile:
+			hint = "Check the passphrase, it can also be given via " + secret.PassphraseEnv + "."
+		case secret.BackendCommand:
+			hint = "Run secret_command manually to check that it prints the secret."
+		}
+
+		d.add("Secret backend", doctorFail, backend+": "+err.Error(), hint)
+
+		return
+	}
+
+	d.secretsOK = true
+
+	d.add("Secret backend", doctorPass, backend, "")
+}
+
+// checkSecrets checks that all required secrets are set and valid. Only valid secrets are used by later checks.
+func (d *doctor) checkSecrets() {
+	if !d.secretsOK {
+		d.add("Secrets", doctorSkip, "secret backend is not available", "")
+		return
+	}
+
+	steamRequired := d.config == nil || !d.config.SkipSteamServicesCheck || !d.config.SkipSteamUserCheck
+
+	for _, key := range secret.Keys() {
+		check := "Secret " + string(key)
+
+		exists, err := secret.Exists(key)
+		if err != nil {
+			d.add(check, doctorFail, err.Error(), "Fix the secret backend first.")
+			continue
+		}
+
+		if !exists {
+			if key == secret.SteamAPIKey && !steamRequired {
+				d.add(check, doctorSkip, "not set, not required since Steam checks are disabled", "")
+				continue
+			}
+
+			d.add(check, doctorFail, "not set", "Run dropawp secrets rotate "+string(key)+" to set it.")
+
+			continue
+		}
+
+		var value string
+		value, err = secret.Load(key)
+		if err != nil {
+			d.add(check, doctorFail, err.Error(), "Fix the secret backend first.")
+			continue
+		}
+
+		details := formatSecretScope(key)
+		if d.offline {
+			d.add(check, doctorPass, details+", not validated (offline)", "")
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(d.ctx, doctorCheckTimeout)
+		err = validateSecret(ctx, key, value)
+		cancel()
+
+		if err != nil {
+			d.add(check, doctorFail, err.Error(), "Replace it with dropawp secrets rotate "+string(key)+".")
+			continue
+		}
+
+		switch key {
+		case secret.SteamAPIKey:
+			d.steamKey = value
+		case secret.CSFloatAPIKey:
+			d.floatKey = value
+		}
+
+		err = secret.MarkValidated(key, time.Now())
+		if err != nil {
+			d.add(check, doctorWarn, "valid, "+err.Error(), "Check the permissions of the secrets directory.")
+			continue
+		}
+
+		d.add(check, doctorPass, "valid, "+details, "")
+	}
+}
+
+func (d *doctor) checkAdditionalItems() {
+	if d.config == nil {
+		d.add("Additional items", doctorSkip, "no valid config", "")
+		return
+	}
+
+	if d.config.AdditionalItemsFile == "" {
+		d.add("Additional items", doctorSkip, "no additional items file configured", "")
+		return
+	}
+
+	entries, err := loadAdditionalItems()
+	if err != nil {
+		d.add("Additional items", doctorFail, err.Error(),
+			"Fix the file or remove it with dropawp config edit --clear-items-file.")
+		return
+	}
+
+	d.add("Additional items", doctorPass, fmt.Sprintf(
+		"%d entries (%d items) in %s",
+		len(entries),
+		additional.Quantity(entries),
+		d.config.AdditionalItemsFile,
+	), "")
+}
+
+func (d *doctor) checkSteamProfiles() {
+	switch {
+	case d.config == nil:
+		d.add("Steam profiles", doctorSkip, "no valid config", "")
+		return
+	case d.config.SkipSteamUserCheck:
+		d.add("Steam profiles", doctorSkip, "skip_steam_user_check is enabled", "")
+		return
+	case d.offline:
+		d.add("Steam profiles", doctorSkip, "offline", "")
+		return
+	case d.steamKey == "":
+		d.add("Steam profiles", doctorSkip, "no valid Steam API key", "")
+		return
+	}
+
+	for _, account := range d.config.AllAccounts() {
+		check := "Steam profile " + account.Label
+
+		ctx, cancel := context.WithTimeout(d.ctx, doctorCheckTimeout)
+		public, err := checkSteamUser(ctx, d.steamKey, account)
+		cancel()
+
+		if err != nil {
+			d.add(check, doctorFail, err.Error(), "Make sure the profile is set up.")
+			continue
+		}
+
+		if !public && d.config.InventorySourceOrDefault() == config.InventorySourceCommunity {
+			d.add(check, doctorWarn, fmt.Sprintf("%d is not public", account.SteamID64),
+				"The inventory is fetched via the Web API, set inventory_source to api to skip the community endpoint.")
+			continue
+		}
+
+		d.add(check, doctorPass, fmt.Sprintf("%d", account.SteamID64), "")
+	}
+}
+
+func (d *doctor) checkInventories() {
+	switch {
+	case d.config == nil:
+		d.add("Inventories", doctorSkip, "no valid config", "")
+		return
+	case d.offline:
+		d.add("Inventories", doctorSkip, "offline", "")
+		return
+	}
+
+	appID, contextID := d.config.SteamApp()
+	app := steam.App{ID: appID, ContextID: contextID}
+
+	for _, account := range d.config.AllAccounts() {
+		check := "Inventory " + account.Label
+
+		ctx, cancel := context.WithTimeout(d.ctx, doctorCheckTimeout)
+		inv, err := d.fetchInventory(ctx, account, app)
+		cancel()
+
+		switch {
+		case errors.Is(err, steam.ErrInventoryForbidden):
+			d.add(check, doctorFail, err.Error(),
+				"Make the inventory public in the Steam privacy settings or set inventory_source to api.")
+		case errors.Is(err, steam.ErrInventoryRateLimited):
+			d.add(check, doctorWarn, err.Error(), "Wait a few minutes or set inventory_source to api.")
+		case err != nil:
+			d.add(check, doctorFail, err.Error(), "Check app_id and context_id and your network connection.")
+		default:
+			d.add(check, doctorPass, fmt.Sprintf("%d items (%s)", len(inv.AllItems), app), "")
+		}
+	}
+}
+
+// fetchInventory uses the configured inventory source without the fallback of run,
+// so problems with the community endpoint are reported.
+func (d *doctor) fetchInventory(ctx context.Context, account config.Account, app steam.App) (*steam.CSInventory, error) {
+	if d.config.InventorySourceOrDefault() == config.InventorySourceCommunity {
+		return steam.GetInventory(ctx, account.SteamID64, app)
+	}
+
+	if d.steamKey == "" {
+		return nil, fmt.Errorf("secret %s is required for inventory_source api", secret.SteamAPIKey)
+	}
+
+	return steam.GetInventoryWithAPIKey(ctx, d.steamKey, account.SteamID64, app)
+}
+
+// checkCSFloat measures the latency of the listings endpoint used for pricing.
+// The API key itself is validated by checkSecrets, the listings endpoint also answers unauthenticated requests.
+func (d *doctor) checkCSFloat() {
+	switch {
+	case d.offline:
+		d.add("CSFloat API", doctorSkip, "offline", "")
+		return
+	case d.floatKey == "":
+		d.add("CSFloat API", doctorSkip, "no valid CSFloat API key", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, doctorCheckTimeout)
+	defer cancel()
+
+	latency, err := csfloat.Ping(ctx, d.floatKey)
+	if err != nil {
+		d.add("CSFloat API", doctorFail, err.Error(),
+			"Check your network connection and https://csfloat.com for outages.")
+		return
+	}
+
+	d.add("CSFloat API", doctorPass, "latency "+latency.Round(time.Millisecond).String(), "")
+}
+
+func (d *doctor) print() error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, tabwriterPadding, ' ', 0)
+
+	_, err := fmt.Fprintln(w, "Check\tStatus\tDetails")
+	if err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	_, err = fmt.Fprintln(w, "-----\t------\t-------")
+	if err != nil {
+		return fmt.Errorf("failed to write separator: %w", err)
+	}
+
+	for _, r := range d.results {
+		_, err = fmt.Fprintf(w, "%s\t%s\t%s\n", r.Check, r.Status, r.Details)
+		if err != nil {
+			return fmt.Errorf("failed to write result row: %w", err)
+		}
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+
+	hints := make([]doctorResult, 0, len(d.results))
+	for _, r := range d.results {
+		if r.Hint != "" && r.Status != doctorPass {
+			hints = append(hints, r)
+		}
+	}
+
+	if len(hints) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Println("Hints:")
+
+	for _, r := range hints {
+		fmt.Printf("  %s: %s\n", r.Check, r.Hint)
+	}
+
+	return nil
+}
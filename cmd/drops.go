@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/devusSs/dropawp/internal/config"
+	"github.com/devusSs/dropawp/internal/drops"
+	"github.com/devusSs/dropawp/internal/exchange"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var dropsShowEvents bool
+
+var dropsCmd = &cobra.Command{
+	Use:   "drops",
+	Short: "Summarizes the value of weekly drops over time.",
+	Long: `Summarizes the value of weekly drops detected between runs.
+
+New items are classified as drop, purchase, trade in or returned (e.g. taken out of a storage unit)
+by comparing asset ids with the previous run. At most two drops per account are counted per week.
+Steam does not expose where an item came from, so the classification is a best guess.
+All items are tracked regardless of the item filters, items without a price are logged with a value of 0.
+Values are shown in the display currency.`,
+	PreRun: func(_ *cobra.Command, _ []string) {
+		var err error
+		cfg, err = config.Read()
+		cobra.CheckErr(err)
+	},
+	Run: func(_ *cobra.Command, _ []string) {
+		events, err := drops.Read(cfg.ProjectName)
+		cobra.CheckErr(err)
+
+		if len(events) == 0 {
+			cobra.CheckErr(errors.New("no drops detected yet, drops are detected starting with the second run"))
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		var conversion *exchange.Conversion
+		conversion, err = getConversion(ctx)
+		cobra.CheckErr(err)
+
+		if dropsShowEvents {
+			err = printDropEvents(events, conversion)
+			cobra.CheckErr(err)
+
+			fmt.Println()
+		}
+
+		err = printDropWeeks(drops.Weekly(events), conversion)
+		cobra.CheckErr(err)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dropsCmd)
+
+	dropsCmd.Flags().
+		BoolVar(&dropsShowEvents, "events", false, "List all detected new and removed items")
+}
+
+func printDropEvents(events []drops.Event, conversion *exchange.Conversion) error {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"Detected At", "Account", "Kind", "Item", fmt.Sprintf("Value (%s)", conversion.To)})
+
+	for _, e := range events {
+		err := table.Append(
+			[]string{
+				e.DetectedAt.Format(time.RFC3339),
+				e.Account,
+				string(e.Kind),
+				e.MarketHashName,
+				formatPrice(conversion.Convert(e.Value)),
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to append event to table: %w", err)
+		}
+	}
+
+	err := table.Render()
+	if err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+
+	return nil
+}
+
+// printDropWeeks converts the weekly sums, the cumulative value is converted from the USD sum.
+func printDropWeeks(weeks []drops.Week, conversion *exchange.Conversion) error {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{
+		"Week Of",
+		"Drops",
+		fmt.Sprintf("Value (%s)", conversion.To),
+		fmt.Sprintf("Cumulative (%s)", conversion.To),
+	})
+
+	totalDrops := 0
+	totalValue := 0
+
+	for _, w := range weeks {
+		totalDrops += w.Drops
+		totalValue += w.Value
+
+		err := table.Append(
+			[]string{
+				w.Start.Format(time.DateOnly),
+				strconv.Itoa(w.Drops),
+				formatPrice(conversion.Convert(w.Value)),
+				formatPrice(conversion.Convert(totalValue)),
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to append week to table: %w", err)
+		}
+	}
+
+	table.Footer([]string{"Total", strconv.Itoa(totalDrops), formatPrice(conversion.Convert(totalValue)), ""})
+
+	err := table.Render()
+	if err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+
+	return nil
+}
@@ -1,7 +1,14 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/devusSs/dropawp/internal/config"
 	"github.com/devusSs/dropawp/internal/secret"
@@ -11,6 +18,13 @@ import (
 var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initializes your dropawp tracking project and needed secrets.",
+	Long: `Initializes your dropawp tracking project and needed secrets.
+
+By default every value is prompted for. Use --non-interactive to provision machines from scripts:
+config values are taken from --set flags and DROPAWP_<FIELD> environment variables, secrets from
+--steam-key-stdin / --csfloat-key-stdin or --steam-key-file / --csfloat-key-file.
+If both secrets are read from stdin, the first line is the Steam API key and the second line the CSFloat API key.
+Everything is validated before anything is written.`,
 	Run: func(_ *cobra.Command, _ []string) {
 		_, err := config.Read()
 		if err == nil && !initOverwriteConfig {
@@ -19,6 +33,25 @@ var initCmd = &cobra.Command{
 			)
 		}
 
+		// Secrets may already be needed to resolve vanity names while reading the configuration.
+		err = useSecretStore(&config.Config{
+			SecretBackend: initSecretBackend,
+			SecretFile:    initSecretFile,
+			SecretCommand: initSecretCommand,
+		})
+		cobra.CheckErr(err)
+
+		var provided map[secret.Key]string
+		provided, err = readProvidedSecrets()
+		cobra.CheckErr(err)
+
+		// Steam IDs may be profile URLs or vanity names in all config sources.
+		config.SetSteamIDResolver(func(input string) (uint64, error) {
+			return resolveSteamIDWith(input, func() (string, error) {
+				return initSteamAPIKey(provided)
+			})
+		})
+
 		switch {
 		case initUseEnv:
 			config.SetEnvFile(initEnvFile)
@@ -28,22 +61,42 @@ var initCmd = &cobra.Command{
 			config.SetFile(initFile)
 			cfg, err = config.FromFile()
 			cobra.CheckErr(err)
+		case initNonInteractive:
+			cfg, err = config.FromOverrides()
+			cobra.CheckErr(err)
 		default:
 			cfg, err = config.FromInput()
 			cobra.CheckErr(err)
 		}
 
-		if !cfg.SkipSteamServicesCheck || !cfg.SkipSteamUserCheck {
-			err = checkOrInsertSecret(secret.SteamAPIKey, initOverwriteSecrets)
+		if initSecretBackend != "" {
+			cfg.SecretBackend = initSecretBackend
+			cfg.SecretFile = initSecretFile
+			cfg.SecretCommand = initSecretCommand
+
+			secret.UseNamespace(cfg.ProjectName)
+		} else {
+			err = useSecretStore(cfg)
 			cobra.CheckErr(err)
 		}
 
-		err = checkOrInsertSecret(secret.CSFloatAPIKey, initOverwriteSecrets)
+		var secrets []initSecret
+		secrets, err = collectInitSecrets(cfg, provided)
+		cobra.CheckErr(err)
+
+		err = saveInitSecrets(secrets)
 		cobra.CheckErr(err)
 
 		err = config.Write(cfg)
 		cobra.CheckErr(err)
 
+		if initJSON {
+			err = printInitSummary(cfg, secrets)
+			cobra.CheckErr(err)
+
+			return
+		}
+
 		fmt.Println()
 		fmt.Println("Configuration initialized successfully.")
 	},
@@ -56,15 +109,32 @@ var (
 	initUseFile          bool
 	initFile             string
 	initOverwriteSecrets bool
+	initSecretBackend    string
+	initSecretFile       string
+	initSecretCommand    string
+	initNonInteractive   bool
+	initJSON             bool
 )
 
+// initSecretSource holds the flags a secret can be provided with instead of being prompted for.
+type initSecretSource struct {
+	flag  string
+	stdin bool
+	file  string
+}
+
+var initSecretSources = map[secret.Key]*initSecretSource{
+	secret.SteamAPIKey:   {flag: "steam-key"},
+	secret.CSFloatAPIKey: {flag: "csfloat-key"},
+}
+
 func init() {
 	rootCmd.AddCommand(initCmd)
 
 	initCmd.Flags().
 		BoolVar(&initOverwriteConfig, "overwrite-config", false, "Overwrite existing configuration files")
 	initCmd.Flags().
-		BoolVar(&initUseEnv, "use-env", false, "Use environment variables for configuration")
+		BoolVar(&initUseEnv, "use-env", false, "Use DROPAWP_<FIELD> environment variables for configuration")
 	initCmd.Flags().
 		StringVar(&initEnvFile, "env-file", "", "Path to the environment file if desired")
 	initCmd.Flags().
@@ -74,10 +144,283 @@ func init() {
 	initCmd.Flags().
 		BoolVar(&initOverwriteSecrets, "overwrite-secrets", false, "Overwrite existing secrets")
 
-	initCmd.MarkFlagsMutuallyExclusive("use-env", "use-file")
+	initCmd.Flags().
+		StringVar(&initSecretBackend, "secret-backend", "",
+			"Secret backend to use (keyring/env/file/command), defaults to keyring")
+	initCmd.Flags().
+		StringVar(&initSecretFile, "secret-file", "",
+			"Path to the encrypted secrets file of the file backend")
+	initCmd.Flags().
+		StringVar(&initSecretCommand, "secret-command", "",
+			"Command of the command backend, {key} is replaced with the secret key (e.g., 'pass show dropawp/{key}')")
+
+	initCmd.Flags().
+		BoolVar(&initNonInteractive, "non-interactive", false,
+			"Never prompt, take config values from --set flags and DROPAWP_<FIELD> environment variables")
+	initCmd.Flags().
+		BoolVar(&initJSON, "json", false, "Print a machine-readable summary as JSON")
+
+	for _, key := range secret.Keys() {
+		source := initSecretSources[key]
+
+		initCmd.Flags().
+			BoolVar(&source.stdin, source.flag+"-stdin", false, fmt.Sprintf("Read secret %s from stdin", key))
+		initCmd.Flags().
+			StringVar(&source.file, source.flag+"-file", "", fmt.Sprintf("Read secret %s from a file", key))
+
+		initCmd.MarkFlagsMutuallyExclusive(source.flag+"-stdin", source.flag+"-file")
+	}
+
+	initCmd.MarkFlagsMutuallyExclusive("use-env", "use-file", "non-interactive")
 	initCmd.MarkFlagsRequiredTogether("use-file", "file")
 }
 
+// Statuses of secrets in the init summary.
+const (
+	initSecretProvided  = "provided"
+	initSecretPrompted  = "prompted"
+	initSecretExisting  = "existing"
+	initSecretInherited = "inherited"
+	initSecretNotSet    = "not_set"
+)
+
+// initSecret is a secret handled by init. Value is only set if a new value has to be stored.
+type initSecret struct {
+	Key    secret.Key
+	Value  string
+	Status string
+}
+
+func (s initSecret) String() string {
+	return fmt.Sprintf("initSecret{Key: %s, Value: %s, Status: %s}", s.Key, secret.Mask(s.Value), s.Status)
+}
+
+// initPromptedSecrets holds secrets prompted for before the project name is known,
+// e.g. to resolve vanity names. They are stored in the project namespace by saveInitSecrets.
+var initPromptedSecrets = make(map[secret.Key]string)
+
+// initSteamAPIKey returns the Steam API key to resolve vanity names with, without storing it.
+func initSteamAPIKey(provided map[secret.Key]string) (string, error) {
+	if value, ok := provided[secret.SteamAPIKey]; ok {
+		return value, nil
+	}
+
+	if value, ok := initPromptedSecrets[secret.SteamAPIKey]; ok {
+		return value, nil
+	}
+
+	value, err := optionalSecret(secret.SteamAPIKey)
+	if err != nil || value != "" {
+		return value, err
+	}
+
+	if initNonInteractive {
+		return "", fmt.Errorf(
+			"secret %s is needed to resolve vanity names, provide it with --steam-key-stdin or --steam-key-file",
+			secret.SteamAPIKey,
+		)
+	}
+
+	value, err = secret.GetInput(fmt.Sprintf("Enter value for secret %s", secret.SteamAPIKey))
+	if err != nil {
+		return "", fmt.Errorf("failed to get input for secret %s: %w", secret.SteamAPIKey, err)
+	}
+
+	initPromptedSecrets[secret.SteamAPIKey] = value
+
+	return value, nil
+}
+
+// readProvidedSecrets reads the secrets given via the stdin and file flags.
+// Secrets read from stdin are read line by line in the order of secret.Keys.
+func readProvidedSecrets() (map[secret.Key]string, error) {
+	provided := make(map[secret.Key]string)
+
+	var stdin *bufio.Scanner
+	for _, key := range secret.Keys() {
+		source := initSecretSources[key]
+
+		var value string
+		switch {
+		case source.stdin:
+			if stdin == nil {
+				stdin = bufio.NewScanner(os.Stdin)
+			}
+
+			if !stdin.Scan() {
+				err := stdin.Err()
+				if err == nil {
+					err = errors.New("stdin has no more lines")
+				}
+
+				return nil, fmt.Errorf("failed to read secret %s from stdin: %w", key, err)
+			}
+
+			value = stdin.Text()
+		case source.file != "":
+			data, err := os.ReadFile(source.file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read secret %s: %w", key, err)
+			}
+
+			value = string(data)
+		default:
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return nil, fmt.Errorf("secret %s is empty", key)
+		}
+
+		provided[key] = value
+	}
+
+	return provided, nil
+}
+
+// collectInitSecrets decides which secrets have to be stored and validates all new values
+// before anything is written.
+func collectInitSecrets(c *config.Config, provided map[secret.Key]string) ([]initSecret, error) {
+	required := map[secret.Key]bool{
+		secret.SteamAPIKey:   !c.SkipSteamServicesCheck || !c.SkipSteamUserCheck,
+		secret.CSFloatAPIKey: true,
+	}
+
+	secrets := make([]initSecret, 0, len(secret.Keys()))
+	for _, key := range secret.Keys() {
+		s, err := collectInitSecret(key, provided[key], required[key])
+		if err != nil {
+			return nil, err
+		}
+
+		secrets = append(secrets, s)
+	}
+
+	stored := false
+	for _, s := range secrets {
+		if s.Value == "" {
+			continue
+		}
+
+		stored = true
+
+		err := validateSecret(context.Background(), s.Key, s.Value)
+		if err != nil {
+			return nil, fmt.Errorf("secret %s is invalid: %w", s.Key, err)
+		}
+	}
+
+	backend := secret.Current().Name()
+	if stored && (backend == secret.BackendEnv || backend == secret.BackendCommand) {
+		return nil, fmt.Errorf("%w: secret backend %s cannot store secrets", secret.ErrReadOnly, backend)
+	}
+
+	return secrets, nil
+}
+
+func collectInitSecret(key secret.Key, provided string, required bool) (initSecret, error) {
+	if provided != "" {
+		return initSecret{Key: key, Value: provided, Status: initSecretProvided}, nil
+	}
+
+	if prompted, ok := initPromptedSecrets[key]; ok {
+		return initSecret{Key: key, Value: prompted, Status: initSecretPrompted}, nil
+	}
+
+	exists, err := secret.Exists(key)
+	if err != nil {
+		return initSecret{}, fmt.Errorf("failed to check secret %s: %w", key, err)
+	}
+
+	// Without a terminal existing secrets can only be overwritten by providing them.
+	if exists && (!initOverwriteSecrets || initNonInteractive) {
+		status := initSecretExisting
+
+		var ns string
+		ns, err = secret.Source(key)
+		if err == nil && ns != secret.Namespace() {
+			status = initSecretInherited
+		}
+
+		return initSecret{Key: key, Status: status}, nil
+	}
+
+	if !exists && !required {
+		return initSecret{Key: key, Status: initSecretNotSet}, nil
+	}
+
+	if initNonInteractive {
+		flag := initSecretSources[key].flag
+
+		return initSecret{}, fmt.Errorf("secret %s is not set, provide it with --%s-stdin or --%s-file", key, flag, flag)
+	}
+
+	var value string
+	value, err = secret.GetInput(fmt.Sprintf("Enter value for secret %s", key))
+	if err != nil {
+		return initSecret{}, fmt.Errorf("failed to get input for secret %s: %w", key, err)
+	}
+
+	return initSecret{Key: key, Value: value, Status: initSecretPrompted}, nil
+}
+
+func saveInitSecrets(secrets []initSecret) error {
+	for _, s := range secrets {
+		if s.Value == "" {
+			continue
+		}
+
+		err := secret.Save(s.Key, s.Value)
+		if err != nil {
+			return fmt.Errorf("failed to save secret %s: %w", s.Key, err)
+		}
+
+		err = secret.MarkValidated(s.Key, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type initSummary struct {
+	ProjectName   string              `json:"project_name"`
+	ConfigFile    string              `json:"config_file"`
+	SecretBackend string              `json:"secret_backend"`
+	Secrets       []initSecretSummary `json:"secrets"`
+}
+
+type initSecretSummary struct {
+	Name        secret.Key `json:"name"`
+	Status      string     `json:"status"`
+	Fingerprint string     `json:"fingerprint,omitempty"`
+}
+
+func printInitSummary(c *config.Config, secrets []initSecret) error {
+	summary := initSummary{
+		ProjectName:   c.ProjectName,
+		ConfigFile:    c.FilePath(),
+		SecretBackend: secret.Current().Name(),
+		Secrets:       make([]initSecretSummary, 0, len(secrets)),
+	}
+
+	for _, s := range secrets {
+		status := initSecretSummary{Name: s.Key, Status: s.Status}
+		if s.Value != "" {
+			status.Fingerprint = secret.Fingerprint(s.Value)
+		}
+
+		summary.Secrets = append(summary.Secrets, status)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(summary)
+}
+
 func checkOrInsertSecret(key secret.Key, overwrite bool) error {
 	exists, err := secret.Exists(key)
 	if err != nil {
@@ -91,10 +434,20 @@ func checkOrInsertSecret(key secret.Key, overwrite bool) error {
 			return fmt.Errorf("failed to get input for secret %s: %w", key, err)
 		}
 
+		err = validateSecret(context.Background(), key, value)
+		if err != nil {
+			return fmt.Errorf("secret %s is invalid: %w", key, err)
+		}
+
 		err = secret.Save(key, value)
 		if err != nil {
 			return fmt.Errorf("failed to save secret %s: %w", key, err)
 		}
+
+		err = secret.MarkValidated(key, time.Now())
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
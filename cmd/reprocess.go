@@ -0,0 +1,354 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/devusSs/dropawp/internal/additional"
+	"github.com/devusSs/dropawp/internal/archive"
+	"github.com/devusSs/dropawp/internal/config"
+	"github.com/devusSs/dropawp/internal/exchange"
+	"github.com/devusSs/dropawp/internal/fees"
+	"github.com/devusSs/dropawp/internal/pricing"
+	"github.com/devusSs/dropawp/internal/steam"
+	"github.com/devusSs/dropawp/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reprocessOverwrite    bool
+	reprocessPrintResults bool
+)
+
+var reprocessCmd = &cobra.Command{
+	Use:   "reprocess <snapshot>",
+	Short: "Rebuilds a snapshot from its archived raw responses.",
+	Long: `Rebuilds a snapshot from the raw inventory and pricing responses archived next to it
+using the current parsing code. Raw responses are only archived if archive_raw_responses is enabled.
+
+The snapshot can be given as path or as file name within the storage directory of the project.
+Prices which were taken from the price cache have no raw response, the original price is kept for them.
+The trade ban action and item filters of the original run are used, not the current config.`,
+	Args: cobra.ExactArgs(1),
+	PreRun: func(_ *cobra.Command, _ []string) {
+		var err error
+		cfg, err = config.Read()
+		cobra.CheckErr(err)
+	},
+	Run: func(_ *cobra.Command, args []string) {
+		snapshotPath, err := storage.ResolvePath(cfg.ProjectName, args[0])
+		cobra.CheckErr(err)
+
+		var snapshot *storage.Inventory
+		snapshot, err = storage.Read(snapshotPath)
+		cobra.CheckErr(err)
+
+		var raw *archive.Archive
+		raw, err = archive.Read(storage.RawArchivePath(snapshotPath))
+		cobra.CheckErr(err)
+
+		var reprocessed *storage.Inventory
+		reprocessed, err = reprocessSnapshot(snapshot, raw)
+		cobra.CheckErr(err)
+
+		outputPath := storage.ReprocessedPath(snapshotPath)
+		if reprocessOverwrite {
+			outputPath = snapshotPath
+		}
+
+		err = storage.WriteFile(outputPath, reprocessed)
+		cobra.CheckErr(err)
+
+		if reprocessPrintResults {
+			feeProvider := feeProviderName()
+			if reprocessed.FeeSchedule != nil {
+				feeProvider = reprocessed.FeeSchedule.Provider
+			}
+
+			err = printItems(reprocessed.Items, snapshotConversion(reprocessed), feeProvider)
+			cobra.CheckErr(err)
+
+			fmt.Println()
+		}
+
+		// The exchange rate of the original run is kept, so both values use the same conversion.
+		conversion := snapshotConversion(reprocessed)
+
+		fmt.Printf(
+			"Items: %d -> %d, value: %s -> %s %s\n",
+			snapshot.Total.Amount,
+			reprocessed.Total.Amount,
+			formatPrice(conversion.Convert(snapshot.Total.Value)),
+			formatPrice(conversion.Convert(reprocessed.Total.Value)),
+			conversion.To,
+		)
+		fmt.Println("Reprocessed snapshot written to", outputPath)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reprocessCmd)
+
+	reprocessCmd.Flags().
+		BoolVar(&reprocessOverwrite, "overwrite", false, "Replace the snapshot instead of writing a .reprocessed.json file")
+	reprocessCmd.Flags().
+		BoolVar(&reprocessPrintResults, "print-results", false, "Print the reprocessed items")
+}
+
+// reprocessSnapshot parses the archived responses of a snapshot again.
+// Exchange rate, fee schedule, settings and trade bans of the original run are kept.
+func reprocessSnapshot(snapshot *storage.Inventory, raw *archive.Archive) (*storage.Inventory, error) {
+	settings := snapshotSettings(snapshot)
+
+	accountItems := make(map[string][]steam.CSInventoryItem)
+	accountAllItems := make(map[string][]steam.CSInventoryItem)
+	accounts := make([]config.Account, 0, len(snapshot.Accounts))
+	tradeBanned := make(map[string]bool, len(snapshot.Accounts))
+
+	for _, holdings := range snapshot.Accounts {
+		if holdings.SteamID64 == 0 {
+			continue
+		}
+
+		account := config.Account{Label: holdings.Account, SteamID64: holdings.SteamID64}
+
+		inv, err := parseArchivedInventory(raw, account)
+		if err != nil {
+			return nil, err
+		}
+
+		accounts = append(accounts, account)
+		tradeBanned[account.Label] = holdings.TradeBanned
+		accountItems[account.Label] = selectAccountItems(account.Label, inv, settings)
+		accountAllItems[account.Label] = inv.AllItems
+	}
+
+	// Additional items are not part of any response, they are taken from the snapshot.
+	originalPrices := make(map[string]storage.InventoryItem)
+	additionalEntries := make([]additional.Entry, 0)
+	for _, item := range snapshot.Items {
+		if !item.ManualPrice {
+			originalPrices[item.MarketHashName] = item
+		}
+
+		if item.Account != config.AdditionalItemsAccountLabel {
+			continue
+		}
+
+		additionalEntries = append(additionalEntries, snapshotAdditionalEntry(item))
+	}
+
+	marketHashNames := make([]string, 0)
+	for _, items := range accountItems {
+		for _, item := range items {
+			marketHashNames = append(marketHashNames, item.MarketHashName)
+		}
+	}
+
+	for _, entry := range additionalEntries {
+		if entry.PriceOverride == nil {
+			marketHashNames = append(marketHashNames, entry.MarketHashName)
+		}
+	}
+
+	providerName := raw.PricingProvider
+	if providerName == "" {
+		providerName = pricingProviderName()
+	}
+
+	provider, err := newPricingProvider(providerName, raw.AppID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[string]int)
+	liquidationPrices := make(map[string]int)
+	itemsNoPrice := make(map[string]string)
+	itemsNoLiquidationPrice := make(map[string]string)
+	kept := 0
+
+	for _, name := range marketHashNames {
+		if _, ok := prices[name]; ok {
+			continue
+		}
+
+		if _, ok := itemsNoPrice[name]; ok {
+			continue
+		}
+
+		original, hasOriginal := originalPrices[name]
+
+		var price int
+		price, err = provider.ArchivedPrice(raw, name)
+		switch {
+		case err == nil:
+		case errors.Is(err, pricing.ErrNotArchived) && hasOriginal:
+			price = original.Price
+			kept++
+		default:
+			itemsNoPrice[name] = err.Error()
+			continue
+		}
+
+		prices[name] = price
+
+		var liquidationPrice int
+		liquidationPrice, err = provider.ArchivedLiquidationPrice(raw, name)
+		switch {
+		case err == nil, errors.Is(err, pricing.ErrNotSupported):
+		case errors.Is(err, pricing.ErrNotArchived) && hasOriginal:
+			liquidationPrice = original.LiquidationPrice
+		default:
+			itemsNoLiquidationPrice[name] = err.Error()
+		}
+
+		liquidationPrices[name] = liquidationPrice
+	}
+
+	if len(itemsNoPrice) > 0 {
+		fmt.Println("Items with no price:")
+		for item, reason := range itemsNoPrice {
+			fmt.Println("-", item, ":", reason)
+		}
+	}
+
+	if len(itemsNoLiquidationPrice) > 0 {
+		fmt.Println("Items with no liquidation value:")
+		for item, reason := range itemsNoLiquidationPrice {
+			fmt.Println("-", item, ":", reason)
+		}
+	}
+
+	if kept > 0 {
+		fmt.Printf("Kept the original price of %d items without archived response\n", kept)
+	}
+
+	feeSchedule, err := snapshotFeeSchedule(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	storageItems := make([]storage.InventoryItem, 0, len(prices))
+	for _, account := range accounts {
+		storageItems = append(
+			storageItems,
+			toStorageItems(
+				account.Label,
+				accountItems[account.Label],
+				prices,
+				liquidationPrices,
+				feeSchedule,
+				tradeBanned[account.Label] && settings.TradeBanAction == config.TradeBanActionZeroLiquidation,
+			)...,
+		)
+	}
+
+	storageItems = append(
+		storageItems,
+		toAdditionalStorageItems(additionalEntries, prices, liquidationPrices, feeSchedule)...,
+	)
+
+	holdings, total := summarizeHoldings(accounts, tradeBanned, storageItems)
+
+	assets := make([]storage.Asset, 0)
+	for _, account := range accounts {
+		assets = append(assets, toStorageAssets(account.Label, accountAllItems[account.Label], prices)...)
+	}
+
+	return &storage.Inventory{
+		Timestamp:    snapshot.Timestamp,
+		Items:        storageItems,
+		Accounts:     holdings,
+		Total:        total,
+		ExchangeRate: snapshot.ExchangeRate,
+		FeeSchedule:  snapshot.FeeSchedule,
+		Settings:     settings,
+		Assets:       assets,
+	}, nil
+}
+
+// parseArchivedInventory prefers the community response and falls back to Web API pages.
+func parseArchivedInventory(raw *archive.Archive, account config.Account) (*steam.CSInventory, error) {
+	key := strconv.FormatUint(account.SteamID64, 10)
+
+	responses := raw.Find(archive.KindInventory, key)
+	if len(responses) > 0 {
+		inv, err := steam.ParseInventory(responses[len(responses)-1].Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse archived inventory of account %s: %w", account.Label, err)
+		}
+
+		return inv, nil
+	}
+
+	responses = raw.Find(archive.KindInventoryAPI, key)
+	if len(responses) == 0 {
+		return nil, fmt.Errorf("no archived inventory of account %s", account.Label)
+	}
+
+	pages := make([][]byte, 0, len(responses))
+	for _, r := range responses {
+		pages = append(pages, r.Body)
+	}
+
+	inv, err := steam.ParseInventoryPages(pages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse archived inventory of account %s: %w", account.Label, err)
+	}
+
+	return inv, nil
+}
+
+// snapshotSettings falls back to the current config for snapshots created before settings were stored.
+func snapshotSettings(snapshot *storage.Inventory) *storage.Settings {
+	if snapshot.Settings == nil {
+		return currentSettings()
+	}
+
+	return snapshot.Settings
+}
+
+func snapshotFeeSchedule(snapshot *storage.Inventory) (fees.Schedule, error) {
+	if snapshot.FeeSchedule == nil {
+		return getFeeSchedule()
+	}
+
+	return fees.Schedule{
+		Percent: snapshot.FeeSchedule.Percent,
+		Minimum: snapshot.FeeSchedule.Minimum,
+	}, nil
+}
+
+func snapshotConversion(snapshot *storage.Inventory) *exchange.Conversion {
+	if snapshot.ExchangeRate == nil {
+		return exchange.Identity(config.DefaultCurrency)
+	}
+
+	return &exchange.Conversion{
+		From:     snapshot.ExchangeRate.From,
+		To:       snapshot.ExchangeRate.To,
+		Rate:     snapshot.ExchangeRate.Rate,
+		Date:     snapshot.ExchangeRate.Date,
+		Provider: exchange.ProviderName(snapshot.ExchangeRate.Provider),
+	}
+}
+
+// snapshotAdditionalEntry restores the additional items entry a snapshot item was created from.
+func snapshotAdditionalEntry(item storage.InventoryItem) additional.Entry {
+	entry := additional.Entry{
+		MarketHashName: item.MarketHashName,
+		Quantity:       item.Amount,
+		Location:       item.Location,
+		PurchasePrice:  item.PurchasePrice,
+		PurchaseDate:   item.PurchaseDate,
+		Notes:          item.Notes,
+	}
+
+	if item.ManualPrice {
+		price := item.Price
+		entry.PriceOverride = &price
+	}
+
+	return entry
+}
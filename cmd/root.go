@@ -3,6 +3,7 @@ package cmd
 import (
 	"os"
 
+	"github.com/devusSs/dropawp/internal/config"
 	"github.com/devusSs/dropawp/internal/system"
 	"github.com/spf13/cobra"
 )
@@ -32,6 +33,19 @@ that would violate the Steam or CSFloat TOS.`,
 	},
 }
 
+var rootConfigOverrides []string
+
+func init() {
+	rootCmd.PersistentFlags().
+		StringArrayVar(&rootConfigOverrides, "set", nil,
+			"Override a config value for this run as name=value, e.g. cooldown_duration=2h (repeatable)")
+
+	cobra.OnInitialize(func() {
+		err := config.SetFlagOverrides(rootConfigOverrides)
+		cobra.CheckErr(err)
+	})
+}
+
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
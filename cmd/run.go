@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -11,9 +10,15 @@ import (
 	"sync"
 	"time"
 
+	"github.com/devusSs/dropawp/internal/additional"
+	"github.com/devusSs/dropawp/internal/archive"
+	"github.com/devusSs/dropawp/internal/cache"
 	"github.com/devusSs/dropawp/internal/config"
-	"github.com/devusSs/dropawp/internal/csfloat"
+	"github.com/devusSs/dropawp/internal/drops"
+	"github.com/devusSs/dropawp/internal/exchange"
+	"github.com/devusSs/dropawp/internal/fees"
 	"github.com/devusSs/dropawp/internal/lastrun"
+	"github.com/devusSs/dropawp/internal/pricing"
 	"github.com/devusSs/dropawp/internal/secret"
 	"github.com/devusSs/dropawp/internal/steam"
 	"github.com/devusSs/dropawp/internal/storage"
@@ -32,6 +37,9 @@ var runCmd = &cobra.Command{
 		cfg, err = config.Read()
 		cobra.CheckErr(err)
 
+		err = useSecretStore(cfg)
+		cobra.CheckErr(err)
+
 		lastRun, err = lastrun.Read()
 		if err != nil {
 			if !errors.Is(err, lastrun.ErrLastRunNotExist) {
@@ -65,6 +73,12 @@ var runCmd = &cobra.Command{
 		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 		defer cancel()
 
+		var recorder *archive.Recorder
+		if cfg.ArchiveRawResponses {
+			recorder = archive.NewRecorder()
+			ctx = archive.WithRecorder(ctx, recorder)
+		}
+
 		if !cfg.SkipSteamServicesCheck {
 			apiKey, err := getSecret(secret.SteamAPIKey)
 			cobra.CheckErr(err)
@@ -73,100 +87,172 @@ var runCmd = &cobra.Command{
 			status, err = steam.GetCSServerStatus(ctx, apiKey)
 			cobra.CheckErr(err)
 
-			if status.Community == "offline" || status.Sessions == "offline" {
-				cobra.CheckErr(
-					fmt.Sprintf(
-						"required Steam services have issues: community: %s, sessions: %s",
-						status.Community,
-						status.Sessions,
-					),
-				)
-			}
+			err = status.CheckServices(cfg.SteamServiceRequirements())
+			cobra.CheckErr(err)
 		}
 
-		if !cfg.SkipSteamUserCheck {
-			apiKey, err := getSecret(secret.SteamAPIKey)
-			cobra.CheckErr(err)
+		accounts := cfg.AllAccounts()
+		settings := currentSettings()
+		accountItems := make(map[string][]steam.CSInventoryItem, len(accounts))
+		// Assets are tracked for all items, drop detection does not depend on the pricing filters.
+		accountAllItems := make(map[string][]steam.CSInventoryItem, len(accounts))
+		accountsTradeBanned := make(map[string]bool, len(accounts))
 
-			var user *steam.UserSummary
-			user, err = steam.GetUserSummary(ctx, apiKey, cfg.SteamID64)
-			cobra.CheckErr(err)
+		// Bans are checked whenever the Steam API key is set, independent of the profile check.
+		steamAPIKey, err := optionalSecret(secret.SteamAPIKey)
+		cobra.CheckErr(err)
+
+		if steamAPIKey == "" && cfg.TradeBanAction != "" && cfg.TradeBanAction != config.TradeBanActionWarn {
+			cobra.CheckErr(
+				fmt.Errorf("trade_ban_action %s requires secret %s to check for trade bans",
+					cfg.TradeBanAction, secret.SteamAPIKey),
+			)
+		}
+
+		for _, account := range accounts {
+			if !cfg.SkipSteamUserCheck {
+				apiKey, err := getSecret(secret.SteamAPIKey)
+				cobra.CheckErr(err)
 
-			if user.CommunityVisibilityState != steam.CommunityVisibilityPublic {
-				cobra.CheckErr(
-					fmt.Sprintf(
-						"steam user profile is not public, visibility state: %s",
-						user.CommunityVisibilityState.String(),
-					),
-				)
+				var public bool
+				public, err = checkSteamUser(ctx, apiKey, account)
+				cobra.CheckErr(err)
+
+				if !public && cfg.InventorySourceOrDefault() == config.InventorySourceCommunity {
+					fmt.Printf("Steam user profile of account %s is not public, its inventory is fetched via the Web API\n",
+						account.Label)
+				}
 			}
 
-			if user.ProfileState != steam.ProfileStateCreated {
-				cobra.CheckErr(
-					fmt.Sprintf(
-						"steam user profile is not set up, profile state: %s",
-						user.ProfileState.String(),
-					),
-				)
+			if steamAPIKey != "" {
+				tradeBanned, err := checkPlayerBans(ctx, steamAPIKey, account)
+				cobra.CheckErr(err)
+
+				accountsTradeBanned[account.Label] = tradeBanned
 			}
+
+			inv, err := getAccountInventory(ctx, account)
+			cobra.CheckErr(err)
+
+			accountItems[account.Label] = selectAccountItems(account.Label, inv, settings)
+			accountAllItems[account.Label] = inv.AllItems
 		}
 
-		inv, err := steam.GetCSInventory(ctx, cfg.SteamID64)
+		additionalEntries, err := loadAdditionalItems()
 		cobra.CheckErr(err)
 
-		items := inv.MarketableItems
-		if !cfg.SkipFilterUntradableItems {
-			items = inv.MarketableAndTradableItems
+		marketHashNames := make(map[string]bool)
+		for _, items := range accountItems {
+			for _, item := range items {
+				marketHashNames[item.MarketHashName] = true
+			}
+		}
+
+		for _, entry := range additionalEntries {
+			if entry.PriceOverride == nil {
+				marketHashNames[entry.MarketHashName] = true
+			}
 		}
 
-		var additionalItems *additionalItems
-		additionalItems, err = loadAdditionalItemsFile()
+		var conversion *exchange.Conversion
+		conversion, err = getConversion(ctx)
 		cobra.CheckErr(err)
 
-		for item := range additionalItems.Items {
-			items = append(items, steam.CSInventoryItem{
-				MarketHashName: item,
-			})
-		}
+		var feeSchedule fees.Schedule
+		feeSchedule, err = getFeeSchedule()
+		cobra.CheckErr(err)
 
-		var apiKey string
-		apiKey, err = getSecret(secret.CSFloatAPIKey)
+		var priceCache *cache.PriceCache
+		priceCache, err = cache.Open(cfg.PriceCacheDuration(), runNoCache)
 		cobra.CheckErr(err)
 
+		var pricer pricing.Provider
+		pricer, err = getPricingProvider()
+		cobra.CheckErr(err)
+
+		pricer = pricing.WithCache(pricer, priceCache)
+
 		itemsPriceMap := make(map[string]int)
-		itemsAmountMap := make(map[string]int)
+		itemsLiquidationPriceMap := make(map[string]int)
 		mutex := &sync.Mutex{}
 		itemsWg := &sync.WaitGroup{}
 		itemsNoPrice := make(map[string]string)
+		itemsNoLiquidationPrice := make(map[string]string)
 
-		for _, item := range items {
+		for marketHashName := range marketHashNames {
 			itemsWg.Add(1)
 			go func() {
 				defer itemsWg.Done()
 
-				var price int
-				price, err = csfloat.GetMedianItemPrice(ctx, apiKey, item.MarketHashName)
-				if err != nil {
+				price, priceErr := pricer.Price(ctx, marketHashName)
+				if priceErr != nil {
 					mutex.Lock()
 					defer mutex.Unlock()
-					itemsNoPrice[item.MarketHashName] = err.Error()
+					itemsNoPrice[marketHashName] = priceErr.Error()
 					return
 				}
 
+				liquidationPrice, liquidationErr := pricer.LiquidationPrice(ctx, marketHashName)
+
 				mutex.Lock()
 				defer mutex.Unlock()
 
-				itemsPriceMap[item.MarketHashName] = price
-				itemsAmountMap[item.MarketHashName]++
+				if liquidationErr != nil && !errors.Is(liquidationErr, pricing.ErrNotSupported) {
+					itemsNoLiquidationPrice[marketHashName] = liquidationErr.Error()
+				}
+
+				itemsPriceMap[marketHashName] = price
+				itemsLiquidationPriceMap[marketHashName] = liquidationPrice
 			}()
 		}
 
 		itemsWg.Wait()
 
+		err = priceCache.Save()
+		cobra.CheckErr(err)
+
+		cacheStats := priceCache.Stats()
+		fmt.Printf("Price cache: %d hits, %d misses\n", cacheStats.Hits, cacheStats.Misses)
+
+		storageItems := make([]storage.InventoryItem, 0, len(itemsPriceMap))
+		assets := make([]storage.Asset, 0)
+		for _, account := range accounts {
+			assets = append(assets, toStorageAssets(account.Label, accountAllItems[account.Label], itemsPriceMap)...)
+		}
+
+		for _, account := range accounts {
+			storageItems = append(
+				storageItems,
+				toStorageItems(
+					account.Label,
+					accountItems[account.Label],
+					itemsPriceMap,
+					itemsLiquidationPriceMap,
+					feeSchedule,
+					accountsTradeBanned[account.Label] &&
+						settings.TradeBanAction == config.TradeBanActionZeroLiquidation,
+				)...,
+			)
+		}
+
+		storageItems = append(
+			storageItems,
+			toAdditionalStorageItems(additionalEntries, itemsPriceMap, itemsLiquidationPriceMap, feeSchedule)...,
+		)
+
+		holdings, total := summarizeHoldings(accounts, accountsTradeBanned, storageItems)
+
 		if runPrintResults {
-			err = printItemMap(itemsPriceMap, itemsAmountMap)
+			err = printItems(storageItems, conversion, feeProviderName())
 			cobra.CheckErr(err)
 
+			if len(holdings) > 1 {
+				fmt.Println()
+
+				err = printHoldings(holdings, total, conversion)
+				cobra.CheckErr(err)
+			}
+
 			fmt.Println()
 			if len(itemsNoPrice) > 0 {
 				fmt.Println("Items with no price:")
@@ -174,6 +260,13 @@ var runCmd = &cobra.Command{
 					fmt.Println("-", item, ":", reason)
 				}
 			}
+
+			if len(itemsNoLiquidationPrice) > 0 {
+				fmt.Println("Items with no liquidation value:")
+				for item, reason := range itemsNoLiquidationPrice {
+					fmt.Println("-", item, ":", reason)
+				}
+			}
 		}
 
 		if runExitOnNoPrice && len(itemsNoPrice) > 0 {
@@ -185,46 +278,43 @@ var runCmd = &cobra.Command{
 			)
 		}
 
-		if len(itemsNoPrice) == len(items) {
+		if len(marketHashNames) > 0 && len(itemsNoPrice) == len(marketHashNames) {
 			cobra.CheckErr(
 				"all items have no price, check network conditions",
 			)
 		}
 
-		storageItems := make([]storage.InventoryItem, 0, len(itemsPriceMap))
-		for _, item := range items {
-			price, ok := itemsPriceMap[item.MarketHashName]
-			if !ok {
-				continue
-			}
-
-			amount, ok := itemsAmountMap[item.MarketHashName]
-			if !ok {
-				continue
-			}
-
-			storageItems = append(
-				storageItems,
-				storage.InventoryItem{
-					IconURL:           item.IconURL,
-					ActionInspectLink: item.ActionInspectLink,
-					Name:              item.Name,
-					NameColor:         item.NameColor,
-					MarketName:        item.MarketName,
-					MarketHashName:    item.MarketHashName,
-					MarketInspectLink: item.MarketInspectLink,
-					Marketable:        item.Marketable,
-					Tradable:          item.Tradable,
-					Amount:            amount,
-					Price:             price,
-					Currency:          "USD",
-				},
-			)
+		inventory := &storage.Inventory{
+			Items:    storageItems,
+			Accounts: holdings,
+			Total:    total,
+			ExchangeRate: &storage.ExchangeRate{
+				From:     conversion.From,
+				To:       conversion.To,
+				Rate:     conversion.Rate,
+				Date:     conversion.Date,
+				Provider: string(conversion.Provider),
+			},
+			FeeSchedule: &storage.FeeSchedule{
+				Provider: feeProviderName(),
+				Percent:  feeSchedule.Percent,
+				Minimum:  feeSchedule.Minimum,
+			},
+			Settings: settings,
+			Assets:   assets,
 		}
 
-		err = storage.Write(cfg.ProjectName, storageItems)
+		err = detectDrops(assets, conversion)
 		cobra.CheckErr(err)
 
+		err = storage.Write(cfg.ProjectName, inventory)
+		cobra.CheckErr(err)
+
+		if recorder != nil {
+			err = writeRawArchive(inventory, pricer.Name(), recorder)
+			cobra.CheckErr(err)
+		}
+
 		err = lastrun.Write(cfg.ProjectName)
 		cobra.CheckErr(err)
 
@@ -235,6 +325,7 @@ var runCmd = &cobra.Command{
 var (
 	runPrintResults  bool
 	runExitOnNoPrice bool
+	runNoCache       bool
 )
 
 func init() {
@@ -243,63 +334,573 @@ func init() {
 	runCmd.Flags().BoolVar(&runPrintResults, "print-results", false, "print results to stdout")
 	runCmd.Flags().
 		BoolVar(&runExitOnNoPrice, "exit-on-no-price", false, "exit if any item has no price")
+	runCmd.Flags().
+		BoolVar(&runNoCache, "no-cache", false, "ignore cached prices and query all prices again")
 }
 
-type additionalItems struct {
-	Items map[string]int `json:"items"`
+// checkSteamUser checks that the profile of the account is set up and reports whether it is public.
+// Non-public profiles are allowed since their inventory can be fetched via the Web API with the same
+// API key, either directly or as fallback of the community endpoint.
+func checkSteamUser(ctx context.Context, apiKey string, account config.Account) (bool, error) {
+	user, err := steam.GetUserSummary(ctx, apiKey, account.SteamID64)
+	if err != nil {
+		return false, fmt.Errorf("failed to get user summary for account %s: %w", account.Label, err)
+	}
+
+	if user.ProfileState != steam.ProfileStateCreated {
+		return false, fmt.Errorf(
+			"steam user profile of account %s is not set up, profile state: %s",
+			account.Label,
+			user.ProfileState.String(),
+		)
+	}
+
+	return user.CommunityVisibilityState == steam.CommunityVisibilityPublic, nil
 }
 
-func (i *additionalItems) String() string {
-	return fmt.Sprintf("%+v", *i)
+// checkPlayerBans prints the ban status of the account and reports whether it is trade banned.
+// It returns an error if the account is trade banned and the configured action is to abort.
+func checkPlayerBans(ctx context.Context, apiKey string, account config.Account) (bool, error) {
+	bans, err := steam.GetPlayerBans(ctx, apiKey, account.SteamID64)
+	if err != nil {
+		return false, fmt.Errorf("failed to get player bans for account %s: %w", account.Label, err)
+	}
+
+	fmt.Printf(
+		"Account %s: VAC banned: %t (%d), game bans: %d, community banned: %t, economy ban: %s\n",
+		account.Label,
+		bans.VACBanned,
+		bans.NumberOfVACBans,
+		bans.NumberOfGameBans,
+		bans.CommunityBanned,
+		bans.EconomyBan,
+	)
+
+	if !bans.TradeBanned() {
+		return false, nil
+	}
+
+	switch cfg.TradeBanAction {
+	case config.TradeBanActionAbort:
+		return true, fmt.Errorf("account %s is trade banned", account.Label)
+	case config.TradeBanActionZeroLiquidation:
+		fmt.Printf("Account %s is trade banned, its liquidation value is set to zero\n", account.Label)
+	default:
+		fmt.Printf("Warning: account %s is trade banned, its items cannot be sold\n", account.Label)
+	}
+
+	return true, nil
 }
 
-func loadAdditionalItemsFile() (*additionalItems, error) {
-	if cfg.AdditionalItemsFile == "" {
-		return &additionalItems{}, nil
+func getAccountInventory(ctx context.Context, account config.Account) (*steam.CSInventory, error) {
+	inv, err := fetchInventory(ctx, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory for account %s: %w", account.Label, err)
+	}
+
+	return inv, nil
+}
+
+// currentSettings returns the settings of the config which are stored in snapshots.
+func currentSettings() *storage.Settings {
+	return &storage.Settings{
+		TradeBanAction:            cfg.TradeBanAction,
+		SkipFilterUntradableItems: cfg.SkipFilterUntradableItems,
+		IncludeTradeHeldItems:     cfg.IncludeTradeHeldItems,
 	}
+}
+
+// selectAccountItems returns the items of an inventory which are priced according to the settings.
+func selectAccountItems(label string, inv *steam.CSInventory, settings *storage.Settings) []steam.CSInventoryItem {
+	items := inv.MarketableItems
+	if !settings.SkipFilterUntradableItems {
+		items = inv.MarketableAndTradableItems
+	}
+
+	return appendTradeHeldItems(label, items, inv.TradeHeldItems, settings)
+}
+
+// fetchInventory uses the configured inventory source. The community endpoint falls back
+// to the Web API if the inventory is not accessible or requests are rate limited.
+func fetchInventory(ctx context.Context, account config.Account) (*steam.CSInventory, error) {
+	appID, contextID := cfg.SteamApp()
+	app := steam.App{ID: appID, ContextID: contextID}
+
+	if cfg.InventorySourceOrDefault() == config.InventorySourceCommunity {
+		inv, err := steam.GetInventory(ctx, account.SteamID64, app)
+		if err == nil {
+			return inv, nil
+		}
 
-	f, err := os.Open(cfg.AdditionalItemsFile)
+		if !errors.Is(err, steam.ErrInventoryForbidden) && !errors.Is(err, steam.ErrInventoryRateLimited) {
+			return nil, err
+		}
+
+		fmt.Printf("Community inventory of account %s unavailable (%v), falling back to Web API\n", account.Label, err)
+	}
+
+	apiKey, err := getSecret(secret.SteamAPIKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return steam.GetInventoryWithAPIKey(ctx, apiKey, account.SteamID64, app)
+}
+
+// writeRawArchive stores the recorded responses next to the snapshot of this run.
+func writeRawArchive(inventory *storage.Inventory, pricingProvider string, recorder *archive.Recorder) error {
+	snapshotPath, err := storage.Path(cfg.ProjectName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open additional items file: %w", err)
+		return err
 	}
-	defer f.Close()
 
-	var items additionalItems
-	err = json.NewDecoder(f).Decode(&items)
+	appID, contextID := cfg.SteamApp()
+
+	err = archive.Write(storage.RawArchivePath(snapshotPath), &archive.Archive{
+		Timestamp:       inventory.Timestamp,
+		AppID:           appID,
+		ContextID:       contextID,
+		PricingProvider: pricingProvider,
+		Responses:       recorder.Responses(),
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode additional items file: %w", err)
+		return fmt.Errorf("failed to write raw archive: %w", err)
+	}
+
+	return nil
+}
+
+// appendTradeHeldItems adds items under a trade cooldown if configured,
+// otherwise it only reports how many of them are excluded.
+func appendTradeHeldItems(
+	label string,
+	items []steam.CSInventoryItem,
+	held []steam.CSInventoryItem,
+	settings *storage.Settings,
+) []steam.CSInventoryItem {
+	if len(held) == 0 {
+		return items
+	}
+
+	if !settings.IncludeTradeHeldItems {
+		fmt.Printf(
+			"Excluding %d trade held items of account %s, set include_trade_held_items to include them\n",
+			len(held),
+			label,
+		)
+		return items
 	}
 
-	if len(items.Items) == 0 {
-		return nil, errors.New("additional items file contains no items")
+	for _, item := range held {
+		// Marketable items are already included if untradable items are not filtered.
+		if settings.SkipFilterUntradableItems && item.Marketable {
+			continue
+		}
+
+		items = append(items, item)
 	}
 
-	return &items, nil
+	return items
+}
+
+// toStorageItems merges items of an account by market hash name and applies their prices.
+// Items without a price are skipped.
+func toStorageItems(
+	account string,
+	items []steam.CSInventoryItem,
+	prices map[string]int,
+	liquidationPrices map[string]int,
+	feeSchedule fees.Schedule,
+	zeroLiquidation bool,
+) []storage.InventoryItem {
+	amounts := make(map[string]int)
+	lockedUntil := make(map[string]time.Time)
+
+	for _, item := range items {
+		// Identical items share a description, each of them has its own asset id.
+		amount := len(item.AssetIDs)
+		if amount == 0 {
+			amount = 1
+		}
+
+		amounts[item.MarketHashName] += amount
+
+		if item.TradeHeld() && item.TradableAfter.After(lockedUntil[item.MarketHashName]) {
+			lockedUntil[item.MarketHashName] = item.TradableAfter
+		}
+	}
+
+	storageItems := make([]storage.InventoryItem, 0, len(amounts))
+	stored := make(map[string]bool, len(amounts))
+
+	for _, item := range items {
+		if stored[item.MarketHashName] {
+			continue
+		}
+
+		price, ok := prices[item.MarketHashName]
+		if !ok {
+			continue
+		}
+
+		liquidationPrice := liquidationPrices[item.MarketHashName]
+		if zeroLiquidation {
+			liquidationPrice = 0
+		}
+
+		storageItems = append(
+			storageItems,
+			storage.InventoryItem{
+				Account:           account,
+				IconURL:           item.IconURL,
+				ActionInspectLink: item.ActionInspectLink,
+				Name:              item.Name,
+				NameColor:         item.NameColor,
+				MarketName:        item.MarketName,
+				MarketHashName:    item.MarketHashName,
+				MarketInspectLink: item.MarketInspectLink,
+				Marketable:        item.Marketable,
+				Tradable:          item.Tradable,
+				Type:              item.Type,
+				Weapon:            item.Weapon,
+				Quality:           item.Quality,
+				Rarity:            item.Rarity,
+				Exterior:          item.Exterior,
+				Collection:        item.Collection,
+				StatTrak:          item.StatTrak,
+				Souvenir:          item.Souvenir,
+				TradableAfter:     lockedUntil[item.MarketHashName],
+				Amount:            amounts[item.MarketHashName],
+				Price:             price,
+				LiquidationPrice:  liquidationPrice,
+				Fee:               feeSchedule.Fee(price),
+				Currency:          config.DefaultCurrency,
+			},
+		)
+
+		stored[item.MarketHashName] = true
+	}
+
+	return storageItems
+}
+
+// toStorageAssets returns one asset per asset id of the items of an account.
+// Items without a price are included with a price of zero.
+func toStorageAssets(account string, items []steam.CSInventoryItem, prices map[string]int) []storage.Asset {
+	assets := make([]storage.Asset, 0, len(items))
+
+	for _, item := range items {
+		for _, assetID := range item.AssetIDs {
+			assets = append(assets, storage.Asset{
+				Account:        account,
+				AssetID:        assetID,
+				MarketHashName: item.MarketHashName,
+				Type:           item.Type,
+				Rarity:         item.Rarity,
+				Price:          prices[item.MarketHashName],
+			})
+		}
+	}
+
+	return assets
+}
+
+// detectDrops compares the assets with the latest snapshot and logs new and removed items.
+// Nothing is logged for the first run of a project. Logged values stay in USD, printed values are converted.
+func detectDrops(assets []storage.Asset, conversion *exchange.Conversion) error {
+	snapshots, err := storage.List(cfg.ProjectName)
+	if err != nil {
+		return err
+	}
+
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	previous, err := storage.Read(snapshots[len(snapshots)-1])
+	if err != nil {
+		return fmt.Errorf("failed to read previous snapshot: %w", err)
+	}
+
+	if len(previous.Assets) == 0 {
+		fmt.Println("Previous snapshot contains no asset ids, skipping drop detection")
+		return nil
+	}
+
+	logged, err := drops.Read(cfg.ProjectName)
+	if err != nil {
+		return err
+	}
+
+	events := drops.Detect(previous.Assets, assets, logged, time.Now())
+
+	err = drops.Append(cfg.ProjectName, events)
+	if err != nil {
+		return fmt.Errorf("failed to log drops: %w", err)
+	}
+
+	for _, event := range events {
+		if event.Kind == drops.KindRemoved {
+			continue
+		}
+
+		fmt.Printf(
+			"New item in account %s (%s): %s, %s %s\n",
+			event.Account,
+			event.Kind,
+			event.MarketHashName,
+			formatPrice(conversion.Convert(event.Value)),
+			conversion.To,
+		)
+	}
+
+	return nil
+}
+
+// summarizeHoldings returns the holdings per account, including additional items if any, and the combined total.
+func summarizeHoldings(
+	accounts []config.Account,
+	tradeBanned map[string]bool,
+	items []storage.InventoryItem,
+) ([]storage.Holdings, storage.Holdings) {
+	holdings := make([]storage.Holdings, 0, len(accounts)+1)
+	index := make(map[string]int, len(accounts)+1)
+
+	for _, account := range accounts {
+		index[account.Label] = len(holdings)
+		holdings = append(holdings, storage.Holdings{
+			Account:     account.Label,
+			SteamID64:   account.SteamID64,
+			TradeBanned: tradeBanned[account.Label],
+		})
+	}
+
+	total := storage.Holdings{Account: config.TotalAccountLabel}
+
+	for _, item := range items {
+		i, ok := index[item.Account]
+		if !ok {
+			index[item.Account] = len(holdings)
+			i = len(holdings)
+			holdings = append(holdings, storage.Holdings{Account: item.Account})
+		}
+
+		holdings[i].Add(item)
+		total.Add(item)
+	}
+
+	return holdings, total
+}
+
+// loadAdditionalItems reads the configured additional items file, if any.
+func loadAdditionalItems() ([]additional.Entry, error) {
+	if cfg.AdditionalItemsFile == "" {
+		return nil, nil
+	}
+
+	return additional.Read(cfg.AdditionalItemsFile)
+}
+
+// toAdditionalStorageItems returns one item per additional items entry, entries are not merged
+// so their metadata is kept. A price override is used as price and liquidation price,
+// entries without any price are skipped.
+func toAdditionalStorageItems(
+	entries []additional.Entry,
+	prices map[string]int,
+	liquidationPrices map[string]int,
+	feeSchedule fees.Schedule,
+) []storage.InventoryItem {
+	storageItems := make([]storage.InventoryItem, 0, len(entries))
+
+	for _, entry := range entries {
+		price, ok := prices[entry.MarketHashName]
+		liquidationPrice := liquidationPrices[entry.MarketHashName]
+
+		if entry.PriceOverride != nil {
+			price = *entry.PriceOverride
+			liquidationPrice = price
+			ok = true
+		}
+
+		if !ok {
+			continue
+		}
+
+		storageItems = append(storageItems, storage.InventoryItem{
+			Account:          config.AdditionalItemsAccountLabel,
+			MarketHashName:   entry.MarketHashName,
+			Amount:           entry.Quantity,
+			Price:            price,
+			LiquidationPrice: liquidationPrice,
+			Fee:              feeSchedule.Fee(price),
+			Currency:         config.DefaultCurrency,
+			Location:         entry.Location,
+			PurchasePrice:    entry.PurchasePrice,
+			PurchaseDate:     entry.PurchaseDate,
+			Notes:            entry.Notes,
+			ManualPrice:      entry.PriceOverride != nil,
+		})
+	}
+
+	return storageItems
+}
+
+func pricingProviderName() string {
+	if cfg.PricingProvider != "" {
+		return cfg.PricingProvider
+	}
+
+	appID, _ := cfg.SteamApp()
+
+	return pricing.DefaultProviderName(appID)
+}
+
+// feeProviderName defaults to the marketplace prices are taken from.
+func feeProviderName() string {
+	if cfg.FeeProvider != "" {
+		return cfg.FeeProvider
+	}
+
+	return pricingProviderName()
+}
+
+func getPricingProvider() (pricing.Provider, error) {
+	name := pricingProviderName()
+
+	var apiKey string
+	if name == pricing.ProviderCSFloat {
+		var err error
+		apiKey, err = getSecret(secret.CSFloatAPIKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	appID, _ := cfg.SteamApp()
+
+	return newPricingProvider(name, appID, apiKey)
+}
+
+// newPricingProvider creates a provider by name. The CSFloat API key may be empty
+// if the provider is only used to parse archived responses.
+func newPricingProvider(name string, appID int, csfloatAPIKey string) (pricing.Provider, error) {
+	var provider pricing.Provider
+	switch name {
+	case pricing.ProviderCSFloat:
+		filter := cfg.CSFloatListingFilter
+		provider = pricing.NewCSFloat(csfloatAPIKey, &filter)
+	case pricing.ProviderSteamMarket:
+		provider = pricing.NewSteamMarket(appID)
+	default:
+		return nil, fmt.Errorf("unknown pricing provider: %s", name)
+	}
+
+	if !provider.SupportsApp(appID) {
+		return nil, fmt.Errorf("pricing provider %s does not support app %d", provider.Name(), appID)
+	}
+
+	return provider, nil
+}
+
+func getConversion(ctx context.Context) (*exchange.Conversion, error) {
+	currency := cfg.DisplayCurrency()
+	if currency == config.DefaultCurrency {
+		return exchange.Identity(currency), nil
+	}
+
+	provider, err := exchange.NewProvider(
+		exchange.ProviderName(cfg.ExchangeRateProvider),
+		cfg.ExchangeRateSource,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exchange rate provider: %w", err)
+	}
+
+	var conversion *exchange.Conversion
+	conversion, err = exchange.GetConversion(ctx, provider, config.DefaultCurrency, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+
+	return conversion, nil
 }
 
 const priceConversionFactor = 100
 
-func printItemMap(pricesMap map[string]int, amountsMap map[string]int) error {
-	if len(pricesMap) == 0 {
+func getFeeSchedule() (fees.Schedule, error) {
+	overrides := make(map[fees.Provider]fees.Schedule, len(cfg.MarketplaceFees))
+	for provider, schedule := range cfg.MarketplaceFees {
+		overrides[fees.Provider(provider)] = fees.Schedule{
+			Percent: schedule.Percent,
+			Minimum: schedule.Minimum,
+		}
+	}
+
+	schedule, err := fees.GetSchedule(fees.Provider(feeProviderName()), overrides)
+	if err != nil {
+		return fees.Schedule{}, fmt.Errorf("failed to get fee schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+func printItems(
+	items []storage.InventoryItem,
+	conversion *exchange.Conversion,
+	feeProvider string,
+) error {
+	if len(items) == 0 {
 		return errors.New("no items with prices to print")
 	}
 
-	if len(amountsMap) == 0 {
-		return errors.New("no items with amounts to print")
+	if conversion == nil {
+		return errors.New("no currency conversion to apply")
 	}
 
 	table := tablewriter.NewWriter(os.Stdout)
-	table.Header([]string{"Item", "Price (USD)", "Amount", "Total Price (USD)"})
+	table.Header(
+		[]string{
+			"Account",
+			"Item",
+			fmt.Sprintf("Price (%s)", conversion.To),
+			fmt.Sprintf("Liquidation (%s)", conversion.To),
+			"Amount",
+			fmt.Sprintf("Total Gross (%s)", conversion.To),
+			fmt.Sprintf("Total Fees (%s)", conversion.To),
+			fmt.Sprintf("Total Net (%s)", conversion.To),
+			fmt.Sprintf("Total Liquidation (%s)", conversion.To),
+		},
+	)
+
+	totalGross := 0
+	totalFees := 0
+	totalLiquidation := 0
+
+	for _, item := range items {
+		price := conversion.Convert(item.Price)
+		fee := conversion.Convert(item.Fee)
+		liquidationPrice := conversion.Convert(item.LiquidationPrice)
+
+		totalGross += price * item.Amount
+		totalFees += fee * item.Amount
+		totalLiquidation += liquidationPrice * item.Amount
+
+		name := item.MarketHashName
+		if item.TradableAfter.After(time.Now()) {
+			name += fmt.Sprintf(" (locked until %s)", item.TradableAfter.Format(time.DateOnly))
+		}
 
-	for item, price := range pricesMap {
-		amount := amountsMap[item]
-		totalPrice := float64(price) * float64(amount) / priceConversionFactor
 		err := table.Append(
 			[]string{
-				item,
-				fmt.Sprintf("%.2f", float64(price)/priceConversionFactor),
-				strconv.Itoa(amount),
-				fmt.Sprintf("%.2f", totalPrice),
+				item.Account,
+				name,
+				formatPrice(price),
+				formatPrice(liquidationPrice),
+				strconv.Itoa(item.Amount),
+				formatPrice(price * item.Amount),
+				formatPrice(fee * item.Amount),
+				formatPrice((price - fee) * item.Amount),
+				formatPrice(liquidationPrice * item.Amount),
 			},
 		)
 		if err != nil {
@@ -307,5 +908,105 @@ func printItemMap(pricesMap map[string]int, amountsMap map[string]int) error {
 		}
 	}
 
-	return table.Render()
+	table.Footer(
+		[]string{
+			"Portfolio",
+			"",
+			"",
+			"",
+			"",
+			formatPrice(totalGross),
+			formatPrice(totalFees),
+			formatPrice(totalGross - totalFees),
+			formatPrice(totalLiquidation),
+		},
+	)
+
+	err := table.Render()
+	if err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+
+	fmt.Printf("\nFees calculated for selling on %s\n", feeProvider)
+
+	if conversion.From != conversion.To {
+		fmt.Printf(
+			"Converted from %s at %.4f (%s, %s)\n",
+			conversion.From,
+			conversion.Rate,
+			conversion.Provider,
+			conversion.Date.Format(time.DateOnly),
+		)
+	}
+
+	return nil
+}
+
+func printHoldings(
+	holdings []storage.Holdings,
+	total storage.Holdings,
+	conversion *exchange.Conversion,
+) error {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header(
+		[]string{
+			"Account",
+			"Steam ID 64",
+			"Amount",
+			fmt.Sprintf("Gross (%s)", conversion.To),
+			fmt.Sprintf("Fees (%s)", conversion.To),
+			fmt.Sprintf("Net (%s)", conversion.To),
+			fmt.Sprintf("Liquidation (%s)", conversion.To),
+		},
+	)
+
+	for _, h := range holdings {
+		steamID := ""
+		if h.SteamID64 != 0 {
+			steamID = strconv.FormatUint(h.SteamID64, 10)
+		}
+
+		account := h.Account
+		if h.TradeBanned {
+			account += " (trade banned)"
+		}
+
+		err := table.Append(
+			[]string{
+				account,
+				steamID,
+				strconv.Itoa(h.Amount),
+				formatPrice(conversion.Convert(h.Value)),
+				formatPrice(conversion.Convert(h.Fees)),
+				formatPrice(conversion.Convert(h.Value - h.Fees)),
+				formatPrice(conversion.Convert(h.LiquidationValue)),
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to append holdings to table: %w", err)
+		}
+	}
+
+	table.Footer(
+		[]string{
+			"Total",
+			"",
+			strconv.Itoa(total.Amount),
+			formatPrice(conversion.Convert(total.Value)),
+			formatPrice(conversion.Convert(total.Fees)),
+			formatPrice(conversion.Convert(total.Value - total.Fees)),
+			formatPrice(conversion.Convert(total.LiquidationValue)),
+		},
+	)
+
+	err := table.Render()
+	if err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+
+	return nil
+}
+
+func formatPrice(price int) string {
+	return fmt.Sprintf("%.2f", float64(price)/priceConversionFactor)
 }
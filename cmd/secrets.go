@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+	"time"
+
+	"github.com/devusSs/dropawp/internal/config"
+	"github.com/devusSs/dropawp/internal/csfloat"
+	"github.com/devusSs/dropawp/internal/secret"
+	"github.com/devusSs/dropawp/internal/steam"
+	"github.com/spf13/cobra"
+)
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "List, validate or rotate the stored secrets.",
+	PersistentPreRun: func(_ *cobra.Command, _ []string) {
+		c, err := config.Read()
+		if err == nil {
+			err = useSecretStore(c)
+			cobra.CheckErr(err)
+		}
+	},
+}
+
+var secretsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show masked secrets and when they were last validated.",
+	Run: func(_ *cobra.Command, _ []string) {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, tabwriterPadding, ' ', 0)
+
+		_, err := fmt.Fprintf(w, "Secret\tBackend\tScope\tValue\tLast Validated\n")
+		cobra.CheckErr(err)
+
+		_, err = fmt.Fprintf(w, "------\t-------\t-----\t-----\t--------------\n")
+		cobra.CheckErr(err)
+
+		for _, key := range secret.Keys() {
+			value, scope := "not set", "-"
+
+			var exists bool
+			exists, err = secret.Exists(key)
+			switch {
+			case err != nil:
+				value = "error: " + err.Error()
+			case exists:
+				var loaded string
+				loaded, err = secret.Load(key)
+				if err != nil {
+					value = "error: " + err.Error()
+					break
+				}
+
+				value = secret.Mask(loaded)
+				scope = formatSecretScope(key)
+			}
+
+			_, err = fmt.Fprintf(
+				w,
+				"%s\t%s\t%s\t%s\t%s\n",
+				key,
+				secret.Current().Name(),
+				scope,
+				value,
+				formatLastValidated(key),
+			)
+			cobra.CheckErr(err)
+		}
+
+		err = w.Flush()
+		cobra.CheckErr(err)
+	},
+}
+
+var secretsCheckCmd = &cobra.Command{
+	Use:   "check [secret...]",
+	Short: "Validate secrets against their services.",
+	Run: func(_ *cobra.Command, args []string) {
+		keys, err := parseSecretKeys(args)
+		cobra.CheckErr(err)
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		failed := 0
+		for _, key := range keys {
+			var exists bool
+			exists, err = secret.Exists(key)
+			cobra.CheckErr(err)
+
+			if !exists {
+				fmt.Printf("%s: not set\n", key)
+				continue
+			}
+
+			var value string
+			value, err = secret.Load(key)
+			cobra.CheckErr(err)
+
+			err = validateSecret(ctx, key, value)
+			if err != nil {
+				fmt.Printf("%s: invalid (%v)\n", key, err)
+				failed++
+				continue
+			}
+
+			err = secret.MarkValidated(key, time.Now())
+			cobra.CheckErr(err)
+
+			fmt.Printf("%s: valid\n", key)
+		}
+
+		if failed > 0 {
+			cobra.CheckErr(fmt.Errorf("%d secrets are invalid", failed))
+		}
+	},
+}
+
+var secretsRotateGlobal bool
+
+var secretsRotateCmd = &cobra.Command{
+	Use:   "rotate <secret>",
+	Short: "Replace a secret, the new value is only stored if it is valid.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		key, err := secret.ParseKey(args[0])
+		cobra.CheckErr(err)
+
+		var value string
+		value, err = secret.GetInput(fmt.Sprintf("Enter new value for secret %s", key))
+		cobra.CheckErr(err)
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		err = validateSecret(ctx, key, value)
+		if err != nil {
+			cobra.CheckErr(fmt.Errorf("new value for %s is invalid, keeping the current value: %w", key, err))
+		}
+
+		if secretsRotateGlobal {
+			secret.UseNamespace(secret.GlobalNamespace)
+		}
+
+		err = secret.Save(key, value)
+		cobra.CheckErr(err)
+
+		err = secret.MarkValidated(key, time.Now())
+		cobra.CheckErr(err)
+
+		fmt.Printf("Secret %s has been rotated (%s).\n", key, formatSecretScope(key))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(secretsCmd)
+
+	secretsCmd.AddCommand(secretsListCmd)
+	secretsCmd.AddCommand(secretsCheckCmd)
+	secretsCmd.AddCommand(secretsRotateCmd)
+
+	secretsRotateCmd.Flags().
+		BoolVar(&secretsRotateGlobal, "global", false, "Store the secret globally so it is inherited by all projects without their own value.")
+}
+
+func parseSecretKeys(names []string) ([]secret.Key, error) {
+	if len(names) == 0 {
+		return secret.Keys(), nil
+	}
+
+	keys := make([]secret.Key, 0, len(names))
+	for _, name := range names {
+		key, err := secret.ParseKey(name)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+const validateSecretTimeout = 10 * time.Second
+
+// validateSecret checks a secret value against the service it belongs to.
+func validateSecret(ctx context.Context, key secret.Key, value string) error {
+	ctx, cancel := context.WithTimeout(ctx, validateSecretTimeout)
+	defer cancel()
+
+	switch key {
+	case secret.SteamAPIKey:
+		return steam.ValidateAPIKey(ctx, value)
+	case secret.CSFloatAPIKey:
+		return csfloat.ValidateAPIKey(ctx, value)
+	default:
+		return fmt.Errorf("no validation available for secret %s", key)
+	}
+}
+
+// formatSecretScope tells whether a secret is set for the current project or inherited globally.
+func formatSecretScope(key secret.Key) string {
+	ns, err := secret.Source(key)
+	if err != nil {
+		return "-"
+	}
+
+	if ns == secret.GlobalNamespace {
+		return "global"
+	}
+
+	return "project " + ns
+}
+
+func formatLastValidated(key secret.Key) string {
+	validated, err := secret.LastValidated(key)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+
+	if validated.IsZero() {
+		return "never"
+	}
+
+	return validated.Format(time.RFC3339)
+}
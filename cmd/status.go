@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/devusSs/dropawp/internal/config"
+	"github.com/devusSs/dropawp/internal/csfloat"
+	"github.com/devusSs/dropawp/internal/secret"
+	"github.com/devusSs/dropawp/internal/steam"
+	"github.com/spf13/cobra"
+)
+
+var statusCmdShowDatacenters bool
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Shows the health of Steam and CSFloat services.",
+	Long: `Shows the health of Steam and CSFloat services.
+
+Exits with an error if any probe failed or a required Steam service is below its minimum state.`,
+	Run: func(_ *cobra.Command, _ []string) {
+		requirements := config.DefaultRequiredSteamServices()
+
+		// Status works without a project, a config which exists but cannot be read is reported.
+		c, err := config.Read()
+		switch {
+		case err == nil:
+			requirements = c.SteamServiceRequirements()
+
+			err = useSecretStore(c)
+			cobra.CheckErr(err)
+		case !errors.Is(err, os.ErrNotExist):
+			fmt.Printf("Warning: %v, using the default Steam service requirements\n", err)
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, tabwriterPadding, ' ', 0)
+
+		_, err = fmt.Fprintln(w, "Check\tStatus\tLatency\tDetails")
+		cobra.CheckErr(err)
+
+		_, err = fmt.Fprintln(w, "-----\t------\t-------\t-------")
+		cobra.CheckErr(err)
+
+		failed := 0
+
+		latency, probeErr := steam.PingCommunity(ctx)
+		if probeErr != nil {
+			failed++
+		}
+
+		err = writeStatusRow(w, "Steam Community", latency, probeErr)
+		cobra.CheckErr(err)
+
+		var status *steam.CSServerStatus
+		status, probeErr = getSteamStatus(ctx)
+		if probeErr != nil {
+			failed++
+
+			err = writeStatusRow(w, "Steam Web API", 0, probeErr)
+			cobra.CheckErr(err)
+		} else {
+			err = writeStatusRow(w, "Steam Web API", status.Latency, nil)
+			cobra.CheckErr(err)
+
+			var failedServices int
+			failedServices, err = writeServiceRows(w, status, requirements)
+			cobra.CheckErr(err)
+
+			failed += failedServices
+		}
+
+		latency, probeErr = getCSFloatLatency(ctx)
+		if probeErr != nil {
+			failed++
+		}
+
+		err = writeStatusRow(w, "CSFloat API", latency, probeErr)
+		cobra.CheckErr(err)
+
+		err = w.Flush()
+		cobra.CheckErr(err)
+
+		if status != nil {
+			err = printStatusDetails(status)
+			cobra.CheckErr(err)
+		}
+
+		if failed > 0 {
+			cobra.CheckErr(fmt.Errorf("%d checks failed", failed))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().
+		BoolVar(&statusCmdShowDatacenters, "datacenters", false, "Show capacity and load of all datacenters")
+}
+
+func getSteamStatus(ctx context.Context) (*steam.CSServerStatus, error) {
+	exists, err := secret.Exists(secret.SteamAPIKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check secret %s: %w", secret.SteamAPIKey, err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("secret %s is not set", secret.SteamAPIKey)
+	}
+
+	var apiKey string
+	apiKey, err = getSecret(secret.SteamAPIKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return steam.GetCSServerStatus(ctx, apiKey)
+}
+
+func getCSFloatLatency(ctx context.Context) (time.Duration, error) {
+	exists, err := secret.Exists(secret.CSFloatAPIKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check secret %s: %w", secret.CSFloatAPIKey, err)
+	}
+
+	if !exists {
+		return 0, fmt.Errorf("secret %s is not set", secret.CSFloatAPIKey)
+	}
+
+	var apiKey string
+	apiKey, err = getSecret(secret.CSFloatAPIKey)
+	if err != nil {
+		return 0, err
+	}
+
+	return csfloat.Ping(ctx, apiKey)
+}
+
+func writeStatusRow(w *tabwriter.Writer, name string, latency time.Duration, err error) error {
+	state := "ok"
+	details := ""
+	if err != nil {
+		state = "fail"
+		details = err.Error()
+	}
+
+	latencyStr := "-"
+	if latency > 0 {
+		latencyStr = latency.Round(time.Millisecond).String()
+	}
+
+	_, err = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, state, latencyStr, details)
+	if err != nil {
+		return fmt.Errorf("failed to write status row: %w", err)
+	}
+
+	return nil
+}
+
+// writeServiceRows writes a row per service and returns the number of required services below their minimum state.
+func writeServiceRows(w *tabwriter.Writer, status *steam.CSServerStatus, requirements map[string]string) (int, error) {
+	failed := 0
+
+	services := make([]string, 0, len(status.Services))
+	for service := range status.Services {
+		services = append(services, service)
+	}
+
+	sort.Strings(services)
+
+	for _, service := range services {
+		details := "not required"
+
+		var err error
+		if minimum, ok := requirements[service]; ok {
+			details = "required " + minimum
+			err = status.CheckServices(map[string]string{service: minimum})
+		}
+
+		state := status.Services[service]
+		if err != nil {
+			state += " (fail)"
+			failed++
+		}
+
+		_, err = fmt.Fprintf(w, "  %s\t%s\t-\t%s\n", service, state, details)
+		if err != nil {
+			return 0, fmt.Errorf("failed to write service row: %w", err)
+		}
+	}
+
+	return failed, nil
+}
+
+// printStatusDetails prints matchmaking and, if requested, datacenter details.
+func printStatusDetails(status *steam.CSServerStatus) error {
+	fmt.Println()
+
+	err := printMatchmaking(status.Matchmaking)
+	if err != nil {
+		return err
+	}
+
+	if !statusCmdShowDatacenters {
+		return nil
+	}
+
+	fmt.Println()
+
+	return printDatacenters(status.Datacenters)
+}
+
+func printMatchmaking(m steam.Matchmaking) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, tabwriterPadding, ' ', 0)
+
+	rows := [][2]string{
+		{"Matchmaking Scheduler", m.Scheduler},
+		{"Online Servers", strconv.Itoa(m.OnlineServers)},
+		{"Online Players", strconv.Itoa(m.OnlinePlayers)},
+		{"Searching Players", strconv.Itoa(m.SearchingPlayers)},
+		{"Average Search Time", (time.Duration(m.SearchSecondsAvg) * time.Second).String()},
+	}
+
+	for _, row := range rows {
+		_, err := fmt.Fprintf(w, "%s\t%s\n", row[0], row[1])
+		if err != nil {
+			return fmt.Errorf("failed to write matchmaking row: %w", err)
+		}
+	}
+
+	return w.Flush()
+}
+
+func printDatacenters(datacenters map[string]steam.Datacenter) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, tabwriterPadding, ' ', 0)
+
+	_, err := fmt.Fprintln(w, "Datacenter\tCapacity\tLoad")
+	if err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	_, err = fmt.Fprintln(w, "----------\t--------\t----")
+	if err != nil {
+		return fmt.Errorf("failed to write separator: %w", err)
+	}
+
+	names := make([]string, 0, len(datacenters))
+	for name := range datacenters {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		dc := datacenters[name]
+
+		_, err = fmt.Fprintf(w, "%s\t%s\t%s\n", name, dc.Capacity, dc.Load)
+		if err != nil {
+			return fmt.Errorf("failed to write datacenter row: %w", err)
+		}
+	}
+
+	return w.Flush()
+}
@@ -3,12 +3,13 @@ module github.com/devusSs/dropawp
 go 1.24.5
 
 require (
-	github.com/caarlos0/env/v11 v11.3.1
+	github.com/BurntSushi/toml v1.5.0
 	github.com/joho/godotenv v1.5.1
 	github.com/olekukonko/tablewriter v1.0.9
 	github.com/spf13/cobra v1.9.1
 	github.com/zalando/go-keyring v0.2.6
 	golang.org/x/term v0.33.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,388 @@
+package additional
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Entry is an item tracked in addition to the Steam inventories, e.g. an item in a storage unit.
+// Prices are given in USD cents. PriceOverride replaces the market price if it is set.
+type Entry struct {
+	MarketHashName string    `json:"market_hash_name"`
+	Quantity       int       `json:"quantity"`
+	Location       string    `json:"location,omitempty"`
+	PurchasePrice  int       `json:"purchase_price,omitempty"`
+	PurchaseDate   time.Time `json:"purchase_date,omitzero"`
+	Notes          string    `json:"notes,omitempty"`
+	PriceOverride  *int      `json:"price_override,omitempty"`
+}
+
+func (e Entry) String() string {
+	override := "-"
+	if e.PriceOverride != nil {
+		override = strconv.Itoa(*e.PriceOverride)
+	}
+
+	return fmt.Sprintf(
+		"Entry{MarketHashName: %s, Quantity: %d, Location: %s, PurchasePrice: %d, PurchaseDate: %s, Notes: %s, PriceOverride: %s}",
+		e.MarketHashName,
+		e.Quantity,
+		e.Location,
+		e.PurchasePrice,
+		e.PurchaseDate.Format(DateFormat),
+		e.Notes,
+		override,
+	)
+}
+
+// Formats the additional items file can be written in, detected by its extension.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatCSV  = "csv"
+)
+
+// DateFormat is the format of purchase dates, RFC 3339 timestamps are accepted as well.
+const DateFormat = "2006-01-02"
+
+// FormatFromPath returns the format of an additional items file based on its extension.
+// Files with any other extension are read as JSON, like before other formats were supported.
+func FormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".csv":
+		return FormatCSV
+	default:
+		return FormatJSON
+	}
+}
+
+// Read reads and validates an additional items file.
+func Read(path string) ([]Entry, error) {
+	format := FormatFromPath(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read additional items file: %w", err)
+	}
+
+	var entries []Entry
+	entries, err = Parse(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode additional items file: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Parse parses and validates additional items.
+//
+// JSON and YAML files contain an items list of entries. The legacy form, items mapping
+// market hash names to quantities, is still supported. CSV files need a header row
+// naming the columns, only market_hash_name is required.
+func Parse(data []byte, format string) ([]Entry, error) {
+	var entries []fileEntry
+	var err error
+
+	switch format {
+	case FormatJSON:
+		entries, err = parseJSON(data)
+	case FormatYAML:
+		entries, err = parseYAML(data)
+	case FormatCSV:
+		entries, err = parseCSV(data)
+	default:
+		return nil, fmt.Errorf("unsupported additional items format '%s'", format)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, errors.New("additional items file contains no items")
+	}
+
+	result := make([]Entry, 0, len(entries))
+	for i, entry := range entries {
+		var e Entry
+		e, err = entry.toEntry()
+		if err != nil {
+			return nil, fmt.Errorf("invalid item %d: %w", i+1, err)
+		}
+
+		result = append(result, e)
+	}
+
+	return result, nil
+}
+
+// Quantity returns the number of items of all entries.
+func Quantity(entries []Entry) int {
+	quantity := 0
+	for _, e := range entries {
+		quantity += e.Quantity
+	}
+
+	return quantity
+}
+
+// fileEntry is an entry as written in a file, before defaults are applied and values are validated.
+type fileEntry struct {
+	MarketHashName string `json:"market_hash_name"`
+	Quantity       *int   `json:"quantity"`
+	Location       string `json:"location"`
+	PurchasePrice  int    `json:"purchase_price"`
+	PurchaseDate   string `json:"purchase_date"`
+	Notes          string `json:"notes"`
+	PriceOverride  *int   `json:"price_override"`
+}
+
+func (e fileEntry) toEntry() (Entry, error) {
+	entry := Entry{
+		MarketHashName: strings.TrimSpace(e.MarketHashName),
+		Quantity:       1,
+		Location:       e.Location,
+		PurchasePrice:  e.PurchasePrice,
+		Notes:          e.Notes,
+		PriceOverride:  e.PriceOverride,
+	}
+
+	if entry.MarketHashName == "" {
+		return Entry{}, errors.New("market_hash_name cannot be empty")
+	}
+
+	if e.Quantity != nil {
+		entry.Quantity = *e.Quantity
+	}
+
+	if entry.Quantity < 1 {
+		return Entry{}, fmt.Errorf("quantity of %s must be at least 1", entry.MarketHashName)
+	}
+
+	if entry.PurchasePrice < 0 {
+		return Entry{}, fmt.Errorf("purchase_price of %s cannot be negative", entry.MarketHashName)
+	}
+
+	if entry.PriceOverride != nil && *entry.PriceOverride < 0 {
+		return Entry{}, fmt.Errorf("price_override of %s cannot be negative", entry.MarketHashName)
+	}
+
+	if e.PurchaseDate != "" {
+		var err error
+		entry.PurchaseDate, err = parseDate(e.PurchaseDate)
+		if err != nil {
+			return Entry{}, fmt.Errorf("invalid purchase_date of %s: %w", entry.MarketHashName, err)
+		}
+	}
+
+	return entry, nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	t, err := time.Parse(DateFormat, s)
+	if err == nil {
+		return t, nil
+	}
+
+	t, err = time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected format %s, got '%s'", DateFormat, s)
+	}
+
+	return t, nil
+}
+
+func parseJSON(data []byte) ([]fileEntry, error) {
+	var file struct {
+		Items json.RawMessage `json:"items"`
+	}
+
+	err := json.Unmarshal(data, &file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode json: %w", err)
+	}
+
+	if len(file.Items) == 0 {
+		return nil, nil
+	}
+
+	var entries []fileEntry
+	err = json.Unmarshal(file.Items, &entries)
+	if err == nil {
+		return entries, nil
+	}
+
+	// Legacy form mapping market hash names to quantities.
+	var quantities map[string]int
+	legacyErr := json.Unmarshal(file.Items, &quantities)
+	if legacyErr != nil {
+		return nil, fmt.Errorf("failed to decode items: %w", err)
+	}
+
+	names := make([]string, 0, len(quantities))
+	for name := range quantities {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	entries = make([]fileEntry, 0, len(quantities))
+	for _, name := range names {
+		quantity := quantities[name]
+		entries = append(entries, fileEntry{MarketHashName: name, Quantity: &quantity})
+	}
+
+	return entries, nil
+}
+
+// parseYAML converts YAML into JSON, so both share the same structure and legacy handling.
+func parseYAML(data []byte) ([]fileEntry, error) {
+	var v any
+	err := yaml.Unmarshal(data, &v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode yaml: %w", err)
+	}
+
+	if v == nil {
+		return nil, nil
+	}
+
+	data, err = json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to normalize yaml: %w", err)
+	}
+
+	return parseJSON(data)
+}
+
+// csvColumns are the supported columns of CSV files.
+var csvColumns = []string{
+	"market_hash_name",
+	"quantity",
+	"location",
+	"purchase_price",
+	"purchase_date",
+	"notes",
+	"price_override",
+}
+
+func parseCSV(data []byte) ([]fileEntry, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(csvColumns, name) {
+			return nil, fmt.Errorf("unknown csv column '%s', expected %s", name, strings.Join(csvColumns, ", "))
+		}
+
+		columns[name] = i
+	}
+
+	if _, ok := columns["market_hash_name"]; !ok {
+		return nil, errors.New("csv header has no market_hash_name column")
+	}
+
+	var entries []fileEntry
+	for {
+		var record []string
+		record, err = r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv: %w", err)
+		}
+
+		var entry fileEntry
+		entry, err = csvEntry(columns, record)
+		if err != nil {
+			line, _ := r.FieldPos(0)
+			return nil, fmt.Errorf("invalid csv line %d: %w", line, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func csvEntry(columns map[string]int, record []string) (fileEntry, error) {
+	value := func(column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+
+		return strings.TrimSpace(record[i])
+	}
+
+	entry := fileEntry{
+		MarketHashName: value("market_hash_name"),
+		Location:       value("location"),
+		PurchaseDate:   value("purchase_date"),
+		Notes:          value("notes"),
+	}
+
+	var err error
+	entry.Quantity, err = parseOptionalInt(value("quantity"))
+	if err != nil {
+		return fileEntry{}, fmt.Errorf("invalid quantity: %w", err)
+	}
+
+	var purchasePrice *int
+	purchasePrice, err = parseOptionalInt(value("purchase_price"))
+	if err != nil {
+		return fileEntry{}, fmt.Errorf("invalid purchase_price: %w", err)
+	}
+
+	if purchasePrice != nil {
+		entry.PurchasePrice = *purchasePrice
+	}
+
+	entry.PriceOverride, err = parseOptionalInt(value("price_override"))
+	if err != nil {
+		return fileEntry{}, fmt.Errorf("invalid price_override: %w", err)
+	}
+
+	return entry, nil
+}
+
+func parseOptionalInt(s string) (*int, error) {
+	if s == "" {
+		return nil, nil //nolint:nilnil // An empty column is not set.
+	}
+
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, fmt.Errorf("expected an integer, got '%s'", s)
+	}
+
+	return &i, nil
+}
@@ -0,0 +1,161 @@
+package archive
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Kind identifies the endpoint a raw response was returned by.
+type Kind string
+
+const (
+	KindInventory        Kind = "inventory"
+	KindInventoryAPI     Kind = "inventory_api"
+	KindCSFloatListings  Kind = "csfloat_listings"
+	KindCSFloatBuyOrders Kind = "csfloat_buy_orders"
+	KindSteamMarketPrice Kind = "steam_market_price"
+)
+
+// Response is a raw response body. Key identifies the request within its kind,
+// e.g. the SteamID64 of an inventory or the market hash name of a price.
+type Response struct {
+	Kind      Kind            `json:"kind"`
+	Key       string          `json:"key"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	Body      json.RawMessage `json:"body"`
+}
+
+func (r Response) String() string {
+	return fmt.Sprintf(
+		"Response{Kind: %s, Key: %s, FetchedAt: %s, Body: %d bytes}",
+		r.Kind,
+		r.Key,
+		r.FetchedAt.Format(time.RFC3339),
+		len(r.Body),
+	)
+}
+
+// Archive holds all raw responses of a run.
+type Archive struct {
+	Timestamp       time.Time  `json:"timestamp"`
+	AppID           int        `json:"app_id"`
+	ContextID       string     `json:"context_id"`
+	PricingProvider string     `json:"pricing_provider"`
+	Responses       []Response `json:"responses"`
+}
+
+func (a *Archive) String() string {
+	return fmt.Sprintf("%+v", *a)
+}
+
+// Find returns all responses of a kind and key in the order they were recorded.
+func (a *Archive) Find(kind Kind, key string) []Response {
+	var responses []Response
+	for _, r := range a.Responses {
+		if r.Kind == kind && r.Key == key {
+			responses = append(responses, r)
+		}
+	}
+
+	return responses
+}
+
+// Recorder collects raw responses. It is safe for concurrent use.
+type Recorder struct {
+	mu        sync.Mutex
+	responses []Response
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Responses returns the recorded responses in the order they were recorded.
+func (r *Recorder) Responses() []Response {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Response(nil), r.responses...)
+}
+
+type recorderKey struct{}
+
+// WithRecorder returns a context which makes Record store responses in r.
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// Record stores a raw response if ctx carries a recorder.
+// Bodies which are not valid JSON are ignored.
+func Record(ctx context.Context, kind Kind, key string, body []byte) {
+	r, ok := ctx.Value(recorderKey{}).(*Recorder)
+	if !ok || r == nil || !json.Valid(body) {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.responses = append(r.responses, Response{
+		Kind:      kind,
+		Key:       key,
+		FetchedAt: time.Now(),
+		Body:      append(json.RawMessage(nil), body...),
+	})
+}
+
+// Write stores the archive gzip compressed at path.
+func Write(path string, a *Archive) error {
+	if a == nil {
+		return errors.New("archive cannot be nil")
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create archive file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+
+	err = json.NewEncoder(gz).Encode(a)
+	if err != nil {
+		return fmt.Errorf("failed to encode archive: %w", err)
+	}
+
+	err = gz.Close()
+	if err != nil {
+		return fmt.Errorf("failed to compress archive: %w", err)
+	}
+
+	return nil
+}
+
+// Read reads an archive written by Write.
+func Read(path string) (*Archive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress archive: %w", err)
+	}
+	defer gz.Close()
+
+	a := &Archive{}
+	err = json.NewDecoder(gz).Decode(a)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode archive: %w", err)
+	}
+
+	return a, nil
+}
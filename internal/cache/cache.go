@@ -0,0 +1,330 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// maxEntryAge is the age after which entries are removed from the cache file,
+// regardless of the TTL used by a single project.
+const maxEntryAge = 7 * 24 * time.Hour
+
+type Entry struct {
+	Provider       string    `json:"provider"`
+	MarketHashName string    `json:"market_hash_name"`
+	Price          int       `json:"price"`
+	FetchedAt      time.Time `json:"fetched_at"`
+}
+
+func (e Entry) String() string {
+	return fmt.Sprintf(
+		"Entry{Provider: %s, MarketHashName: %s, Price: %d, FetchedAt: %s}",
+		e.Provider,
+		e.MarketHashName,
+		e.Price,
+		e.FetchedAt.Format(time.RFC3339),
+	)
+}
+
+// PriceCache is an on-disk price cache shared by all projects and runs.
+// It is safe for concurrent use.
+type PriceCache struct {
+	ttl      time.Duration
+	disabled bool
+
+	mu      sync.Mutex
+	entries map[string]Entry
+	hits    int
+	misses  int
+}
+
+type Stats struct {
+	Hits   int `json:"hits"`
+	Misses int `json:"misses"`
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf("Stats{Hits: %d, Misses: %d}", s.Hits, s.Misses)
+}
+
+// ErrCorrupt is returned if the cache file cannot be decoded.
+var ErrCorrupt = errors.New("cache file is corrupt")
+
+// Open reads the cache file. Lookups are skipped if disabled is true,
+// however new prices are still stored. A corrupt cache file is replaced with an empty cache.
+func Open(ttl time.Duration, disabled bool) (*PriceCache, error) {
+	if ttl <= 0 {
+		return nil, errors.New("ttl must be positive")
+	}
+
+	entries, err := readEntries()
+	if errors.Is(err, ErrCorrupt) {
+		fmt.Fprintf(os.Stderr, "Warning: %v, starting with an empty price cache\n", err)
+
+		entries = make(map[string]Entry)
+		err = nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &PriceCache{
+		ttl:      ttl,
+		disabled: disabled,
+		entries:  entries,
+	}, nil
+}
+
+// Get returns the cached price if it is younger than the TTL.
+func (c *PriceCache) Get(provider string, marketHashName string) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	price, ok := c.lookup(provider, marketHashName)
+	if !ok {
+		c.misses++
+		return 0, false
+	}
+
+	c.hits++
+
+	return price, true
+}
+
+// Lookup is like Get, but the lookup is not counted in the stats.
+func (c *PriceCache) Lookup(provider string, marketHashName string) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lookup(provider, marketHashName)
+}
+
+func (c *PriceCache) lookup(provider string, marketHashName string) (int, bool) {
+	entry, ok := c.entries[entryKey(provider, marketHashName)]
+	if c.disabled || !ok || time.Since(entry.FetchedAt) > c.ttl {
+		return 0, false
+	}
+
+	return entry.Price, true
+}
+
+func (c *PriceCache) Set(provider string, marketHashName string, price int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[entryKey(provider, marketHashName)] = Entry{
+		Provider:       provider,
+		MarketHashName: marketHashName,
+		Price:          price,
+		FetchedAt:      time.Now(),
+	}
+}
+
+func (c *PriceCache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return Stats{Hits: c.hits, Misses: c.misses}
+}
+
+// Save writes the cache file and drops entries older than maxEntryAge.
+// Entries written by other runs since Open are merged, the newer entry is kept per key.
+func (c *PriceCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stored, err := readEntries()
+	if err != nil && !errors.Is(err, ErrCorrupt) {
+		return err
+	}
+
+	for key, entry := range stored {
+		current, ok := c.entries[key]
+		if !ok || entry.FetchedAt.After(current.FetchedAt) {
+			c.entries[key] = entry
+		}
+	}
+
+	for key, entry := range c.entries {
+		if time.Since(entry.FetchedAt) > maxEntryAge {
+			delete(c.entries, key)
+		}
+	}
+
+	return writeEntries(c.entries)
+}
+
+// Summary describes the cache file contents relative to a TTL.
+type Summary struct {
+	Path        string         `json:"path"`
+	Entries     int            `json:"entries"`
+	Fresh       int            `json:"fresh"`
+	Expired     int            `json:"expired"`
+	PerProvider map[string]int `json:"per_provider"`
+	Oldest      time.Time      `json:"oldest"`
+	Newest      time.Time      `json:"newest"`
+}
+
+func (s *Summary) String() string {
+	return fmt.Sprintf("%+v", *s)
+}
+
+func Summarize(ttl time.Duration) (*Summary, error) {
+	entries, err := readEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	var path string
+	path, err = cacheFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Summary{
+		Path:        path,
+		Entries:     len(entries),
+		PerProvider: make(map[string]int),
+	}
+
+	for _, entry := range entries {
+		if time.Since(entry.FetchedAt) > ttl {
+			s.Expired++
+		} else {
+			s.Fresh++
+		}
+
+		s.PerProvider[entry.Provider]++
+
+		if s.Oldest.IsZero() || entry.FetchedAt.Before(s.Oldest) {
+			s.Oldest = entry.FetchedAt
+		}
+
+		if entry.FetchedAt.After(s.Newest) {
+			s.Newest = entry.FetchedAt
+		}
+	}
+
+	return s, nil
+}
+
+func Clear() error {
+	path, err := cacheFilePath()
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete cache file: %w", err)
+	}
+
+	return nil
+}
+
+func entryKey(provider string, marketHashName string) string {
+	return provider + "|" + marketHashName
+}
+
+type cacheFile struct {
+	Entries []Entry `json:"entries"`
+}
+
+func readEntries() (map[string]Entry, error) {
+	path, err := cacheFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]Entry)
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return entries, nil
+		}
+
+		return nil, fmt.Errorf("failed to open cache file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var cf cacheFile
+	err = json.NewDecoder(f).Decode(&cf)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode %s: %w", ErrCorrupt, path, err)
+	}
+
+	for _, entry := range cf.Entries {
+		entries[entryKey(entry.Provider, entry.MarketHashName)] = entry
+	}
+
+	return entries, nil
+}
+
+func writeEntries(entries map[string]Entry) error {
+	path, err := cacheFilePath()
+	if err != nil {
+		return err
+	}
+
+	cf := cacheFile{Entries: make([]Entry, 0, len(entries))}
+	for _, entry := range entries {
+		cf.Entries = append(cf.Entries, entry)
+	}
+
+	// The file is replaced atomically, an interrupted write never leaves a truncated cache file.
+	f, err := os.CreateTemp(filepath.Dir(path), "prices-*.json.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary cache file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	err = json.NewEncoder(f).Encode(cf)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+
+	err = os.Rename(f.Name(), path)
+	if err != nil {
+		return fmt.Errorf("failed to replace cache file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func cacheFilePath() (string, error) {
+	dir, err := setupCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to setup cache directory: %w", err)
+	}
+
+	return filepath.Join(dir, "prices.json"), nil
+}
+
+func setupCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	cacheDir := filepath.Join(home, ".dropawp", "cache")
+
+	err = os.MkdirAll(cacheDir, 0700)
+	if err != nil {
+		return "", fmt.Errorf("failed to create cache directory %s: %w", cacheDir, err)
+	}
+
+	return cacheDir, nil
+}
@@ -2,33 +2,187 @@ package config
 
 import (
 	"crypto/rand"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/devusSs/dropawp/internal/csfloat"
 )
 
 type Config struct {
-	ProjectName               string        `json:"project_name"`
-	CreatedAt                 time.Time     `json:"created_at"`
-	UpdatedAt                 time.Time     `json:"updated_at"`
-	CooldownDuration          time.Duration `json:"cooldown_duration"`
-	SkipSteamServicesCheck    bool          `json:"skip_steam_services_check"`
-	SteamID64                 uint64        `json:"steam_id_64"`
-	SkipSteamUserCheck        bool          `json:"skip_steam_user_check"`
-	SkipFilterUntradableItems bool          `json:"skip_filter_untradable_items"`
-	AdditionalItemsFile       string        `json:"additional_items_file"`
+	ProjectName               string            `json:"project_name"`
+	CreatedAt                 time.Time         `json:"created_at"`
+	UpdatedAt                 time.Time         `json:"updated_at"`
+	CooldownDuration          time.Duration     `json:"cooldown_duration"`
+	SkipSteamServicesCheck    bool              `json:"skip_steam_services_check"`
+	RequiredSteamServices     map[string]string `json:"required_steam_services"`
+	SteamID64                 uint64            `json:"steam_id_64"`
+	Accounts                  []Account         `json:"accounts"`
+	SkipSteamUserCheck        bool              `json:"skip_steam_user_check"`
+	TradeBanAction            string            `json:"trade_ban_action"`
+	SkipFilterUntradableItems bool              `json:"skip_filter_untradable_items"`
+	AdditionalItemsFile       string            `json:"additional_items_file"`
+	IncludeTradeHeldItems     bool              `json:"include_trade_held_items"`
+
+	AppID           int    `json:"app_id"`
+	ContextID       string `json:"context_id"`
+	InventorySource string `json:"inventory_source"`
+	PricingProvider string `json:"pricing_provider"`
+
+	CSFloatListingFilter csfloat.ListingFilter `json:"csfloat_listing_filter"`
+
+	Currency             string `json:"currency"`
+	ExchangeRateProvider string `json:"exchange_rate_provider"`
+	ExchangeRateSource   string `json:"exchange_rate_source"`
+
+	FeeProvider     string                 `json:"fee_provider"`
+	MarketplaceFees map[string]FeeSchedule `json:"marketplace_fees"`
+
+	PriceCacheTTL time.Duration `json:"price_cache_ttl"`
+
+	ArchiveRawResponses bool `json:"archive_raw_responses"`
+
+	SecretBackend        string        `json:"secret_backend"`
+	SecretFile           string        `json:"secret_file"`
+	SecretCommand        string        `json:"secret_command"`
+	SecretCommandTimeout time.Duration `json:"secret_command_timeout"`
 
 	filePath string
+	origins  map[string]string
 }
 
 func (c *Config) String() string {
 	return fmt.Sprintf("%+v", *c)
 }
 
+// DefaultRequiredSteamServices fails the services check only if the services needed to fetch inventories are offline.
+func DefaultRequiredSteamServices() map[string]string {
+	return map[string]string{
+		"SessionsLogon":  "delayed",
+		"SteamCommunity": "delayed",
+	}
+}
+
+// SteamServiceRequirements returns the configured minimum service states or DefaultRequiredSteamServices.
+func (c *Config) SteamServiceRequirements() map[string]string {
+	if len(c.RequiredSteamServices) == 0 {
+		return DefaultRequiredSteamServices()
+	}
+
+	return c.RequiredSteamServices
+}
+
+// Actions taken if a tracked account is trade banned. Bans are checked whenever a Steam API key is set.
+const (
+	TradeBanActionWarn            = "warn"
+	TradeBanActionZeroLiquidation = "zero_liquidation"
+	TradeBanActionAbort           = "abort"
+)
+
+// Sources inventories are fetched from. The community endpoint falls back to the Web API
+// if it is rate limited or the inventory is not accessible.
+const (
+	InventorySourceCommunity = "community"
+	InventorySourceAPI       = "api"
+)
+
+// InventorySourceOrDefault returns the configured inventory source or the community endpoint.
+func (c *Config) InventorySourceOrDefault() string {
+	if c.InventorySource == "" {
+		return InventorySourceCommunity
+	}
+
+	return c.InventorySource
+}
+
+// Account is an additional Steam account tracked within a project.
+type Account struct {
+	Label     string `json:"label"`
+	SteamID64 uint64 `json:"steam_id_64"`
+}
+
+func (a Account) String() string {
+	return fmt.Sprintf("Account{Label: %s, SteamID64: %d}", a.Label, a.SteamID64)
+}
+
+const (
+	// MainAccountLabel is the label of the account configured via SteamID64.
+	MainAccountLabel = "main"
+	// AdditionalItemsAccountLabel is the label of items from the additional items file.
+	AdditionalItemsAccountLabel = "additional"
+	// TotalAccountLabel is the label of the combined holdings of all accounts.
+	TotalAccountLabel = "total"
+)
+
+// AllAccounts returns the main account followed by all additional accounts.
+func (c *Config) AllAccounts() []Account {
+	accounts := make([]Account, 0, len(c.Accounts)+1)
+	accounts = append(accounts, Account{Label: MainAccountLabel, SteamID64: c.SteamID64})
+
+	return append(accounts, c.Accounts...)
+}
+
+// DefaultCurrency is the currency prices are queried in and the display currency if none is configured.
+const DefaultCurrency = "USD"
+
+// DisplayCurrency returns the configured display currency or DefaultCurrency.
+func (c *Config) DisplayCurrency() string {
+	if c.Currency == "" {
+		return DefaultCurrency
+	}
+
+	return c.Currency
+}
+
+// DefaultPriceCacheTTL is the time cached prices are used for if no TTL is configured.
+const DefaultPriceCacheTTL = time.Hour
+
+// PriceCacheDuration returns the configured price cache TTL or DefaultPriceCacheTTL.
+func (c *Config) PriceCacheDuration() time.Duration {
+	if c.PriceCacheTTL == 0 {
+		return DefaultPriceCacheTTL
+	}
+
+	return c.PriceCacheTTL
+}
+
+const (
+	// DefaultAppID is the Steam app tracked if none is configured (Counter-Strike 2).
+	DefaultAppID = 730
+	// DefaultContextID is the inventory context used if none is configured.
+	DefaultContextID = "2"
+)
+
+// SteamApp returns the configured Steam app id and inventory context id or their defaults.
+func (c *Config) SteamApp() (int, string) {
+	appID := c.AppID
+	if appID == 0 {
+		appID = DefaultAppID
+	}
+
+	contextID := c.ContextID
+	if contextID == "" {
+		contextID = DefaultContextID
+	}
+
+	return appID, contextID
+}
+
+// FeeSchedule overrides the default seller fee of a marketplace.
+// Minimum is given in cents.
+type FeeSchedule struct {
+	Percent float64 `json:"percent"`
+	Minimum int     `json:"minimum"`
+}
+
+func (f *FeeSchedule) String() string {
+	return fmt.Sprintf("%+v", *f)
+}
+
 func Write(c *Config) error {
 	if c == nil {
 		return errors.New("config cannot be nil")
@@ -39,14 +193,35 @@ func Write(c *Config) error {
 		return fmt.Errorf("validation error: %w", err)
 	}
 
+	// The config is written back in the format it was read in.
+	path := c.filePath
+	if path == "" {
+		path, err = defaultConfigFilePath()
+		if err != nil {
+			return err
+		}
+	}
+
+	var format string
+	format, err = FormatFromPath(path)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	data, err = Encode(c, format)
+	if err != nil {
+		return err
+	}
+
 	var f *os.File
-	f, err = createConfigFile()
+	f, err = createConfigFile(path)
 	if err != nil {
 		return fmt.Errorf("failed to create config file: %w", err)
 	}
 	defer f.Close()
 
-	err = json.NewEncoder(f).Encode(c)
+	_, err = f.Write(data)
 	if err != nil {
 		return fmt.Errorf("failed to write config to file: %w", err)
 	}
@@ -54,26 +229,51 @@ func Write(c *Config) error {
 	return nil
 }
 
+// Read reads the config file from the config directory and applies DROPAWP_* environment variables
+// and flag overrides (see SetFlagOverrides) on top of it.
+// The file may be written in JSON, YAML or TOML, see configFileNames.
 func Read() (*Config, error) {
+	return read(true)
+}
+
+// ReadStored reads the config file without applying overrides, e.g. to edit and write it back.
+func ReadStored() (*Config, error) {
+	return read(false)
+}
+
+func read(withOverrides bool) (*Config, error) {
 	f, err := openConfigFile()
 	if err != nil {
 		return nil, fmt.Errorf("failed to open config file: %w", err)
 	}
 	defer f.Close()
 
-	c := &Config{}
-	err = json.NewDecoder(f).Decode(c)
+	var fields map[string]any
+	fields, err = readFields(f)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode config file: %w", err)
 	}
 
-	var dir string
-	dir, err = setupConfigDir()
+	origins := make(map[string]string, len(fields))
+	for name := range fields {
+		origins[name] = OriginFile
+	}
+
+	if withOverrides {
+		err = applyOverrides(fields, origins)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply overrides: %w", err)
+		}
+	}
+
+	var c *Config
+	c, err = fieldsToConfig(fields)
 	if err != nil {
-		return nil, fmt.Errorf("failed to setup config directory: %w", err)
+		return nil, fmt.Errorf("failed to decode config file: %w", err)
 	}
 
-	c.filePath = filepath.Join(dir, "config.json")
+	c.filePath = f.Name()
+	c.origins = origins
 
 	err = c.validate()
 	if err != nil {
@@ -83,6 +283,11 @@ func Read() (*Config, error) {
 	return c, nil
 }
 
+// FilePath returns the path the config was read from or is written to.
+func (c *Config) FilePath() string {
+	return c.filePath
+}
+
 func Delete(c *Config) error {
 	if c == nil {
 		return errors.New("config cannot be nil")
@@ -154,7 +359,7 @@ func FromInput() (*Config, error) {
 	}
 
 	var steamID64Str string
-	steamID64Str, err = getInput("Enter Steam ID64 (required)")
+	steamID64Str, err = getInput("Enter Steam ID64, profile URL or vanity name (required)")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get Steam ID64: %w", err)
 	}
@@ -164,7 +369,7 @@ func FromInput() (*Config, error) {
 	}
 
 	var steamID64 uint64
-	steamID64, err = parseUint64(steamID64Str)
+	steamID64, err = steamIDResolver(steamID64Str)
 	if err != nil {
 		return nil, fmt.Errorf("invalid Steam ID64: %w", err)
 	}
@@ -237,10 +442,19 @@ func FromInput() (*Config, error) {
 	return c, nil
 }
 
+// SetSteamIDResolver sets the function used to turn user input and Steam IDs given as string
+// in config files or overrides into a SteamID64. By default only a raw SteamID64 is accepted.
+func SetSteamIDResolver(resolver func(input string) (uint64, error)) {
+	steamIDResolver = resolver
+}
+
+var steamIDResolver = parseUint64
+
 func SetFile(f string) {
 	file = f
 }
 
+// FromFile reads the config file set by SetFile. The format is detected by its extension.
 func FromFile() (*Config, error) {
 	f, err := os.Open(file)
 	if err != nil {
@@ -248,8 +462,14 @@ func FromFile() (*Config, error) {
 	}
 	defer f.Close()
 
-	c := &Config{}
-	err = json.NewDecoder(f).Decode(c)
+	var fields map[string]any
+	fields, err = readFields(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode config file: %w", err)
+	}
+
+	var c *Config
+	c, err = fieldsToConfig(fields)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode config file: %w", err)
 	}
@@ -274,6 +494,21 @@ func FromFile() (*Config, error) {
 
 var file string
 
+func readFields(f *os.File) (map[string]any, error) {
+	format, err := FormatFromPath(f.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	data, err = io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", f.Name(), err)
+	}
+
+	return decodeFields(data, format)
+}
+
 func generateRandomString(minLength, maxLength int) (string, error) {
 	if minLength < 0 || maxLength < 0 {
 		return "", errors.New("lengths must be non-negative")
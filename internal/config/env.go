@@ -1,12 +1,10 @@
 package config
 
 import (
-	"errors"
 	"fmt"
 	"path/filepath"
 	"time"
 
-	"github.com/caarlos0/env/v11"
 	"github.com/joho/godotenv"
 )
 
@@ -14,16 +12,43 @@ func SetEnvFile(file string) {
 	envFile = file
 }
 
+// FromEnv creates a config from DROPAWP_* environment variables, see EnvName.
+// Nested values like accounts have to be given as JSON.
 func FromEnv() (*Config, error) {
 	err := loadEnvFile()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load environment file: %w", err)
 	}
 
-	c := &Config{}
-	err = parseEnv(c)
+	return FromOverrides()
+}
+
+// FromOverrides creates a config from flag overrides (see SetFlagOverrides) and DROPAWP_* environment
+// variables without prompting. A random project name and the minimum cooldown are used if they are not given.
+func FromOverrides() (*Config, error) {
+	fields := make(map[string]any)
+	origins := make(map[string]string)
+
+	err := applyOverrides(fields, origins)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse environment variables: %w", err)
+		return nil, fmt.Errorf("failed to parse overrides: %w", err)
+	}
+
+	var c *Config
+	c, err = fieldsToConfig(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse overrides: %w", err)
+	}
+
+	if c.ProjectName == "" {
+		c.ProjectName, err = generateRandomString(minProjectNameLength, maxProjectNameLength)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate random project name: %w", err)
+		}
+	}
+
+	if c.CooldownDuration == 0 {
+		c.CooldownDuration = minCooldownDuration
 	}
 
 	var dir string
@@ -35,6 +60,7 @@ func FromEnv() (*Config, error) {
 	c.CreatedAt = time.Now()
 	c.UpdatedAt = time.Now()
 	c.filePath = filepath.Join(dir, "config.json")
+	c.origins = origins
 
 	err = c.validate()
 	if err != nil {
@@ -58,17 +84,3 @@ func loadEnvFile() error {
 
 	return nil
 }
-
-func parseEnv(v any) error {
-	if v == nil {
-		return errors.New("nil value provided for parsing")
-	}
-
-	opts := env.Options{Prefix: "DROPAWP_", RequiredIfNoDef: true}
-	err := env.ParseWithOptions(v, opts)
-	if err != nil {
-		return fmt.Errorf("failed to parse environment variables: %w", err)
-	}
-
-	return nil
-}
@@ -0,0 +1,307 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats config files can be written in. The format of a file is detected by its extension.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+// Formats returns all supported config file formats.
+func Formats() []string {
+	return []string{FormatJSON, FormatYAML, FormatTOML}
+}
+
+// FormatFromPath returns the format of a config file based on its extension.
+func FormatFromPath(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON, nil
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".toml":
+		return FormatTOML, nil
+	default:
+		return "", fmt.Errorf("unsupported config file extension of %s, expected .json, .yaml, .yml or .toml", path)
+	}
+}
+
+// durationFields are the paths of all duration fields. They accept integer nanoseconds
+// or human-readable durations like 30m or 2d in all formats.
+var durationFields = [][]string{
+	{"cooldown_duration"},
+	{"price_cache_ttl"},
+	{"secret_command_timeout"},
+	{"csfloat_listing_filter", "max_listing_age"},
+}
+
+// Decode parses a config file in the given format.
+// The config is not validated, the file path and times are not set.
+func Decode(data []byte, format string) (*Config, error) {
+	fields, err := decodeFields(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return fieldsToConfig(fields)
+}
+
+// decodeFields parses a config file into its raw field values.
+func decodeFields(data []byte, format string) (map[string]any, error) {
+	var fields map[string]any
+	var err error
+
+	switch format {
+	case FormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		// Numbers are kept as is, Steam IDs do not fit into a float64.
+		dec.UseNumber()
+		err = dec.Decode(&fields)
+	case FormatYAML:
+		err = yaml.Unmarshal(data, &fields)
+	case FormatTOML:
+		err = toml.Unmarshal(data, &fields)
+	default:
+		return nil, fmt.Errorf("unsupported config format '%s'", format)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", format, err)
+	}
+
+	if fields == nil {
+		fields = make(map[string]any)
+	}
+
+	return fields, nil
+}
+
+// fieldsToConfig turns raw field values into a config, durations may be given human-readable
+// and Steam IDs in any form the Steam ID resolver accepts (see SetSteamIDResolver).
+func fieldsToConfig(fields map[string]any) (*Config, error) {
+	for _, path := range durationFields {
+		err := updateField(fields, path, parseDurationField)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", strings.Join(path, "."), err)
+		}
+	}
+
+	err := resolveSteamIDFields(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized []byte
+	normalized, err = json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to normalize config: %w", err)
+	}
+
+	c := &Config{}
+	err = json.Unmarshal(normalized, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	return c, nil
+}
+
+// Encode formats a config in the given format. Durations are written human-readable, e.g. 10m0s.
+func Encode(c *Config, format string) ([]byte, error) {
+	if c == nil {
+		return nil, errors.New("config cannot be nil")
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	var fields map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	err = dec.Decode(&fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	for _, path := range durationFields {
+		err = updateField(fields, path, formatDurationField)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", strings.Join(path, "."), err)
+		}
+	}
+
+	switch format {
+	case FormatJSON:
+		data, err = json.MarshalIndent(fields, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode json: %w", err)
+		}
+
+		return append(data, '\n'), nil
+	case FormatYAML:
+		return yaml.Marshal(toPlainValues(fields))
+	case FormatTOML:
+		buf := &bytes.Buffer{}
+		err = toml.NewEncoder(buf).Encode(toPlainValues(fields))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode toml: %w", err)
+		}
+
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported config format '%s'", format)
+	}
+}
+
+// updateField replaces the value at path, missing fields are skipped.
+func updateField(fields map[string]any, path []string, update func(v any) (any, error)) error {
+	for _, name := range path[:len(path)-1] {
+		nested, ok := fields[name].(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		fields = nested
+	}
+
+	name := path[len(path)-1]
+
+	v, ok := fields[name]
+	if !ok || v == nil {
+		return nil
+	}
+
+	updated, err := update(v)
+	if err != nil {
+		return err
+	}
+
+	fields[name] = updated
+
+	return nil
+}
+
+// resolveSteamIDFields resolves Steam IDs given as string, e.g. profile URLs or SteamID3.
+func resolveSteamIDFields(fields map[string]any) error {
+	err := updateField(fields, []string{"steam_id_64"}, resolveSteamIDField)
+	if err != nil {
+		return fmt.Errorf("invalid steam_id_64: %w", err)
+	}
+
+	accounts, ok := fields["accounts"].([]any)
+	if !ok {
+		return nil
+	}
+
+	for i, account := range accounts {
+		a, ok := account.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		err = updateField(a, []string{"steam_id_64"}, resolveSteamIDField)
+		if err != nil {
+			return fmt.Errorf("invalid steam_id_64 of account %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+func resolveSteamIDField(v any) (any, error) {
+	s, ok := v.(string)
+	if !ok {
+		return v, nil
+	}
+
+	id, err := steamIDResolver(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Number(strconv.FormatUint(id, 10)), nil
+}
+
+// parseDurationField turns a duration given as string into nanoseconds.
+func parseDurationField(v any) (any, error) {
+	s, ok := v.(string)
+	if !ok {
+		return v, nil
+	}
+
+	d, err := parseExtendedDuration(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration '%s': %w", s, err)
+	}
+
+	return int64(d), nil
+}
+
+func formatDurationField(v any) (any, error) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return v, nil
+	}
+
+	ns, err := n.Int64()
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration %s: %w", n, err)
+	}
+
+	return time.Duration(ns).String(), nil
+}
+
+// toPlainValues converts json.Number values into integers or floats,
+// YAML and TOML would write them as strings otherwise. Null values are dropped since TOML has no null.
+func toPlainValues(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		plain := make(map[string]any, len(v))
+		for key, value := range v {
+			if value == nil {
+				continue
+			}
+
+			plain[key] = toPlainValues(value)
+		}
+
+		return plain
+	case []any:
+		plain := make([]any, 0, len(v))
+		for _, value := range v {
+			plain = append(plain, toPlainValues(value))
+		}
+
+		return plain
+	case json.Number:
+		i, err := v.Int64()
+		if err == nil {
+			return i
+		}
+
+		f, err := v.Float64()
+		if err == nil {
+			return f
+		}
+
+		return v.String()
+	default:
+		return v
+	}
+}
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Origins of config values. Later layers override earlier ones:
+// stored config file, then DROPAWP_* environment variables, then --set flags.
+const (
+	OriginDefault = "default"
+	OriginFile    = "file"
+	OriginEnv     = "env"
+	OriginFlag    = "flag"
+)
+
+// Field is a top-level config value and the layer it was taken from.
+type Field struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Origin string `json:"origin"`
+}
+
+func (f Field) String() string {
+	return fmt.Sprintf("Field{Name: %s, Value: %s, Origin: %s}", f.Name, f.Value, f.Origin)
+}
+
+// FieldNames returns the names of all top-level config fields in declaration order.
+func FieldNames() []string {
+	t := reflect.TypeFor[Config]()
+
+	names := make([]string, 0, t.NumField())
+	for i := range t.NumField() {
+		name, ok := fieldName(t.Field(i))
+		if ok {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// SetFlagOverrides sets config values given on the command line as name=value pairs.
+// They override the stored config and environment variables.
+func SetFlagOverrides(pairs []string) error {
+	overrides := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		name, value, found := strings.Cut(pair, "=")
+		if !found {
+			return fmt.Errorf("invalid override '%s', expected name=value", pair)
+		}
+
+		name = strings.TrimSpace(name)
+		if _, ok := fieldKinds()[name]; !ok {
+			return fmt.Errorf("unknown config field '%s'", name)
+		}
+
+		overrides[name] = value
+	}
+
+	flagOverrides = overrides
+
+	return nil
+}
+
+var flagOverrides map[string]string
+
+// EnvName returns the environment variable overriding a config field, e.g. DROPAWP_COOLDOWN_DURATION.
+func EnvName(field string) string {
+	return "DROPAWP_" + strings.ToUpper(field)
+}
+
+// Origin returns the layer a top-level config value was taken from.
+func (c *Config) Origin(field string) string {
+	origin, ok := c.origins[field]
+	if !ok {
+		return OriginDefault
+	}
+
+	return origin
+}
+
+// Fields returns all top-level config values with their origin. Durations are human-readable,
+// nested values are formatted as JSON.
+func (c *Config) Fields() ([]Field, error) {
+	data, err := Encode(c, FormatJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]json.RawMessage
+	err = json.Unmarshal(data, &values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode config values: %w", err)
+	}
+
+	names := FieldNames()
+	fields := make([]Field, 0, len(names))
+	for _, name := range names {
+		value := string(values[name])
+
+		var s string
+		if json.Unmarshal(values[name], &s) == nil {
+			value = s
+		} else {
+			buf := &bytes.Buffer{}
+			if json.Compact(buf, values[name]) == nil {
+				value = buf.String()
+			}
+		}
+
+		fields = append(fields, Field{Name: name, Value: value, Origin: c.Origin(name)})
+	}
+
+	return fields, nil
+}
+
+// applyOverrides applies environment and flag overrides to raw field values and records their origin.
+func applyOverrides(fields map[string]any, origins map[string]string) error {
+	for name, kind := range fieldKinds() {
+		value, ok := os.LookupEnv(EnvName(name))
+		if ok {
+			parsed, err := parseOverride(kind, value)
+			if err != nil {
+				return fmt.Errorf("invalid %s: %w", EnvName(name), err)
+			}
+
+			fields[name] = parsed
+			origins[name] = OriginEnv
+		}
+
+		value, ok = flagOverrides[name]
+		if ok {
+			parsed, err := parseOverride(kind, value)
+			if err != nil {
+				return fmt.Errorf("invalid override of %s: %w", name, err)
+			}
+
+			fields[name] = parsed
+			origins[name] = OriginFlag
+		}
+	}
+
+	return nil
+}
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// parseOverride turns an override given as string into a raw field value of the given type.
+// Durations may be human-readable, nested values have to be given as JSON.
+func parseOverride(t reflect.Type, value string) (any, error) {
+	if t == durationType {
+		_, err := strconv.ParseInt(value, 10, 64)
+		if err == nil {
+			return json.Number(value), nil
+		}
+
+		return value, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return value, nil
+	case reflect.Bool:
+		return parseBool(value)
+	case reflect.Int, reflect.Int64:
+		_, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer value: %s", value)
+		}
+
+		return json.Number(value), nil
+	case reflect.Uint64:
+		// Steam IDs may be given in any form, they are resolved by fieldsToConfig.
+		return value, nil
+	case reflect.Struct:
+		if t == reflect.TypeFor[time.Time]() {
+			return value, nil
+		}
+
+		return parseJSONOverride(value)
+	default:
+		return parseJSONOverride(value)
+	}
+}
+
+func parseJSONOverride(value string) (any, error) {
+	dec := json.NewDecoder(strings.NewReader(value))
+	dec.UseNumber()
+
+	var v any
+	err := dec.Decode(&v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON value: %w", err)
+	}
+
+	return v, nil
+}
+
+// fieldKinds maps the name of each top-level config field to its type.
+func fieldKinds() map[string]reflect.Type {
+	t := reflect.TypeFor[Config]()
+
+	kinds := make(map[string]reflect.Type, t.NumField())
+	for i := range t.NumField() {
+		name, ok := fieldName(t.Field(i))
+		if ok {
+			kinds[name] = t.Field(i).Type
+		}
+	}
+
+	return kinds
+}
+
+func fieldName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return "", false
+	}
+
+	return name, true
+}
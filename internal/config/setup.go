@@ -1,19 +1,26 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
-func createConfigFile() (*os.File, error) {
+// configFileNames are the names the config file is looked up by, the first existing file is used.
+var configFileNames = []string{"config.json", "config.yaml", "config.yml", "config.toml"}
+
+func defaultConfigFilePath() (string, error) {
 	configDir, err := setupConfigDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to setup config directory: %w", err)
+		return "", fmt.Errorf("failed to setup config directory: %w", err)
 	}
 
-	configFilePath := filepath.Join(configDir, "config.json")
+	return filepath.Join(configDir, configFileNames[0]), nil
+}
 
+func createConfigFile(configFilePath string) (*os.File, error) {
 	file, err := os.Create(configFilePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create config file %s: %w", configFilePath, err)
@@ -28,14 +35,21 @@ func openConfigFile() (*os.File, error) {
 		return nil, fmt.Errorf("failed to setup config directory: %w", err)
 	}
 
-	configFilePath := filepath.Join(configDir, "config.json")
+	for _, name := range configFileNames {
+		configFilePath := filepath.Join(configDir, name)
 
-	file, err := os.Open(configFilePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open config file %s: %w", configFilePath, err)
+		var file *os.File
+		file, err = os.Open(configFilePath)
+		if err == nil {
+			return file, nil
+		}
+
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to open config file %s: %w", configFilePath, err)
+		}
 	}
 
-	return file, nil
+	return nil, fmt.Errorf("no config file (%s) found in %s: %w", strings.Join(configFileNames, ", "), configDir, err)
 }
 
 func setupConfigDir() (string, error) {
@@ -5,9 +5,14 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 	"unicode"
+
+	"github.com/devusSs/dropawp/internal/csfloat"
+	"github.com/devusSs/dropawp/internal/steam"
 )
 
 func (c *Config) validate() error {
@@ -26,16 +31,65 @@ func (c *Config) validate() error {
 		return fmt.Errorf("invalid cooldown_duration: %w", err)
 	}
 
+	err = validateRequiredSteamServices(c.RequiredSteamServices)
+	if err != nil {
+		return fmt.Errorf("invalid required_steam_services: %w", err)
+	}
+
 	err = validateSteamID64(c.SteamID64)
 	if err != nil {
 		return fmt.Errorf("invalid steam_id_64: %w", err)
 	}
 
+	err = validateTradeBanAction(c.TradeBanAction)
+	if err != nil {
+		return fmt.Errorf("invalid trade_ban_action: %w", err)
+	}
+
+	err = validateAccounts(c.SteamID64, c.Accounts)
+	if err != nil {
+		return fmt.Errorf("invalid accounts: %w", err)
+	}
+
 	err = validateAdditionalItemsFile(c.AdditionalItemsFile)
 	if err != nil {
 		return fmt.Errorf("invalid additional_items_file: %w", err)
 	}
 
+	err = validateCSFloatListingFilter(c.CSFloatListingFilter)
+	if err != nil {
+		return fmt.Errorf("invalid csfloat_listing_filter: %w", err)
+	}
+
+	err = validateCurrency(c.Currency, c.ExchangeRateProvider, c.ExchangeRateSource)
+	if err != nil {
+		return fmt.Errorf("invalid currency: %w", err)
+	}
+
+	err = validateSteamApp(c.AppID, c.ContextID, c.PricingProvider)
+	if err != nil {
+		return fmt.Errorf("invalid steam app: %w", err)
+	}
+
+	err = validateInventorySource(c.InventorySource)
+	if err != nil {
+		return fmt.Errorf("invalid inventory_source: %w", err)
+	}
+
+	err = validateSecretBackend(c.SecretBackend, c.SecretFile, c.SecretCommand, c.SecretCommandTimeout)
+	if err != nil {
+		return fmt.Errorf("invalid secret_backend: %w", err)
+	}
+
+	err = validateMarketplaceFees(c.FeeProvider, c.MarketplaceFees)
+	if err != nil {
+		return fmt.Errorf("invalid marketplace fees: %w", err)
+	}
+
+	if c.PriceCacheTTL < 0 {
+		return errors.New("invalid price_cache_ttl: cannot be negative")
+	}
+
 	return nil
 }
 
@@ -125,3 +179,212 @@ func validateAdditionalItemsFile(filePath string) error {
 
 	return nil
 }
+
+func validateCSFloatListingFilter(filter csfloat.ListingFilter) error {
+	if filter.MaxFailedTrades != nil && *filter.MaxFailedTrades < 0 {
+		return errors.New("max_failed_trades cannot be negative")
+	}
+
+	if filter.MinVerifiedTrades != nil && *filter.MinVerifiedTrades < 0 {
+		return errors.New("min_verified_trades cannot be negative")
+	}
+
+	if filter.MaxListingAge != nil && *filter.MaxListingAge < 0 {
+		return errors.New("max_listing_age cannot be negative")
+	}
+
+	return nil
+}
+
+const currencyRegex = `^[A-Z]{3}$`
+
+var exchangeRateProviders = map[string]bool{
+	"static": true,
+	"ecb":    true,
+}
+
+func validateCurrency(currency string, provider string, source string) error {
+	if currency == "" || currency == DefaultCurrency {
+		return nil
+	}
+
+	regex := regexp.MustCompile(currencyRegex)
+	if !regex.MatchString(currency) {
+		return fmt.Errorf("currency must be an ISO 4217 code matching regex %s, got '%s'", currencyRegex, currency)
+	}
+
+	if provider == "" {
+		return fmt.Errorf("exchange_rate_provider is required for currency %s", currency)
+	}
+
+	if !exchangeRateProviders[provider] {
+		return fmt.Errorf("unknown exchange_rate_provider '%s'", provider)
+	}
+
+	if provider == "static" && source == "" {
+		return errors.New("exchange_rate_source is required for the static exchange rate provider")
+	}
+
+	return nil
+}
+
+var feeProviders = map[string]bool{
+	"steam":   true,
+	"csfloat": true,
+}
+
+const maxFeePercent = 100
+
+func validateMarketplaceFees(provider string, schedules map[string]FeeSchedule) error {
+	if provider != "" && !feeProviders[provider] {
+		return fmt.Errorf("unknown fee_provider '%s'", provider)
+	}
+
+	for name, schedule := range schedules {
+		if !feeProviders[name] {
+			return fmt.Errorf("unknown marketplace_fees provider '%s'", name)
+		}
+
+		if schedule.Percent < 0 || schedule.Percent > maxFeePercent {
+			return fmt.Errorf("percent for %s must be between 0 and %d", name, maxFeePercent)
+		}
+
+		if schedule.Minimum < 0 {
+			return fmt.Errorf("minimum for %s cannot be negative", name)
+		}
+	}
+
+	return nil
+}
+
+const accountLabelRegex = `^[a-z0-9_-]{1,32}$`
+
+func validateAccounts(mainSteamID64 uint64, accounts []Account) error {
+	regex := regexp.MustCompile(accountLabelRegex)
+
+	labels := map[string]bool{
+		MainAccountLabel:            true,
+		AdditionalItemsAccountLabel: true,
+		TotalAccountLabel:           true,
+	}
+	steamIDs := map[uint64]bool{mainSteamID64: true}
+
+	for _, account := range accounts {
+		if !regex.MatchString(account.Label) {
+			return fmt.Errorf("label must match regex %s, got '%s'", accountLabelRegex, account.Label)
+		}
+
+		if labels[account.Label] {
+			return fmt.Errorf("label '%s' is reserved or used more than once", account.Label)
+		}
+
+		err := validateSteamID64(account.SteamID64)
+		if err != nil {
+			return fmt.Errorf("invalid steam_id_64 of account '%s': %w", account.Label, err)
+		}
+
+		if steamIDs[account.SteamID64] {
+			return fmt.Errorf("steam_id_64 %d is used more than once", account.SteamID64)
+		}
+
+		labels[account.Label] = true
+		steamIDs[account.SteamID64] = true
+	}
+
+	return nil
+}
+
+func validateRequiredSteamServices(services map[string]string) error {
+	for service, state := range services {
+		if !slices.Contains(steam.Services(), service) {
+			return fmt.Errorf("unknown service '%s'", service)
+		}
+
+		if !steam.ValidServiceState(state) {
+			return fmt.Errorf("unknown minimum state '%s' for service '%s', expected normal, delayed or offline", state, service)
+		}
+	}
+
+	return nil
+}
+
+func validateTradeBanAction(action string) error {
+	switch action {
+	case "", TradeBanActionWarn, TradeBanActionZeroLiquidation, TradeBanActionAbort:
+		return nil
+	default:
+		return fmt.Errorf(
+			"trade_ban_action must be one of %s, %s or %s, got '%s'",
+			TradeBanActionWarn,
+			TradeBanActionZeroLiquidation,
+			TradeBanActionAbort,
+			action,
+		)
+	}
+}
+
+func validateInventorySource(source string) error {
+	switch source {
+	case "", InventorySourceCommunity, InventorySourceAPI:
+		return nil
+	default:
+		return fmt.Errorf(
+			"inventory_source must be one of %s or %s, got '%s'",
+			InventorySourceCommunity,
+			InventorySourceAPI,
+			source,
+		)
+	}
+}
+
+func validateSecretBackend(backend string, file string, command string, timeout time.Duration) error {
+	if file != "" && backend != "file" {
+		return errors.New("secret_file is only used by the file backend")
+	}
+
+	if command != "" && backend != "command" {
+		return errors.New("secret_command is only used by the command backend")
+	}
+
+	if timeout < 0 {
+		return errors.New("secret_command_timeout cannot be negative")
+	}
+
+	switch backend {
+	case "", "keyring", "env", "file":
+		return nil
+	case "command":
+		if strings.TrimSpace(command) == "" {
+			return errors.New("secret_command is required for the command backend")
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("secret_backend must be one of keyring, env, file or command, got '%s'", backend)
+	}
+}
+
+const contextIDRegex = `^\d+$`
+
+func validateSteamApp(appID int, contextID string, pricingProvider string) error {
+	if appID < 0 {
+		return errors.New("app_id cannot be negative")
+	}
+
+	if contextID != "" && !regexp.MustCompile(contextIDRegex).MatchString(contextID) {
+		return fmt.Errorf("context_id must match regex %s, got '%s'", contextIDRegex, contextID)
+	}
+
+	switch pricingProvider {
+	case "", "steam":
+		return nil
+	case "csfloat":
+		if appID != 0 && appID != DefaultAppID {
+			return fmt.Errorf("pricing_provider csfloat only supports app_id %d", DefaultAppID)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unknown pricing_provider '%s', expected csfloat or steam", pricingProvider)
+	}
+}
@@ -0,0 +1,114 @@
+package csfloat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/devusSs/dropawp/internal/archive"
+)
+
+// GetHighestBuyOrderPrice returns the highest buy order price for the given market hash name.
+// This is the price an item could be sold for instantly and is used as its liquidation value.
+func GetHighestBuyOrderPrice(ctx context.Context, apiKey string, marketHashName string) (int, error) {
+	if ctx == nil {
+		return 0, ErrContextNil
+	}
+
+	if apiKey == "" {
+		return 0, errors.New("apiKey cannot be empty")
+	}
+
+	if marketHashName == "" {
+		return 0, errors.New("marketHashName cannot be empty")
+	}
+
+	u, err := url.Parse(similarBuyOrdersURL)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("limit", "10")
+	u.RawQuery = q.Encode()
+
+	var body []byte
+	body, err = json.Marshal(similarBuyOrdersRequest{MarketHashName: marketHashName})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	err = applyHeaders(req, apiKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to apply headers: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("API returned non-OK status: %s", resp.Status)
+	}
+
+	var respBody []byte
+	respBody, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	archive.Record(ctx, archive.KindCSFloatBuyOrders, marketHashName, respBody)
+
+	return ParseHighestBuyOrderPrice(respBody)
+}
+
+// ParseHighestBuyOrderPrice returns the highest price of a raw buy orders response, e.g. from an archived run.
+func ParseHighestBuyOrderPrice(body []byte) (int, error) {
+	var buyOrdersResponse similarBuyOrdersResponse
+	err := json.Unmarshal(body, &buyOrdersResponse)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode response body: %w", err)
+	}
+
+	highest := 0
+	for _, order := range buyOrdersResponse.Data {
+		if order.Price > highest {
+			highest = order.Price
+		}
+	}
+
+	if highest == 0 {
+		return 0, errors.New("no buy orders found for the given market hash name")
+	}
+
+	return highest, nil
+}
+
+const similarBuyOrdersURL = "https://csfloat.com/api/v1/buy-orders/similar-orders"
+
+type similarBuyOrdersRequest struct {
+	MarketHashName string `json:"market_hash_name"`
+}
+
+type similarBuyOrdersResponse struct {
+	Data []buyOrder `json:"data"`
+}
+
+type buyOrder struct {
+	MarketHashName string `json:"market_hash_name"`
+	Price          int    `json:"price"`
+	Qty            int    `json:"qty"`
+}
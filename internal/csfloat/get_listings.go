@@ -5,14 +5,81 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"sort"
 	"time"
+
+	"github.com/devusSs/dropawp/internal/archive"
 )
 
+// ListingFilter narrows down which listings are used for pricing.
+// Nil limits disable the corresponding filter, a limit of zero is applied like any other value.
+type ListingFilter struct {
+	BuyNowOnly        bool           `json:"buy_now_only"`
+	MaxFailedTrades   *int           `json:"max_failed_trades,omitempty"`
+	MinVerifiedTrades *int           `json:"min_verified_trades,omitempty"`
+	MaxListingAge     *time.Duration `json:"max_listing_age,omitempty"`
+}
+
+func (f *ListingFilter) String() string {
+	return fmt.Sprintf(
+		"ListingFilter{BuyNowOnly: %t, MaxFailedTrades: %s, MinVerifiedTrades: %s, MaxListingAge: %s}",
+		f.BuyNowOnly,
+		FormatLimit(f.MaxFailedTrades),
+		FormatLimit(f.MinVerifiedTrades),
+		FormatLimit(f.MaxListingAge),
+	)
+}
+
+// FormatLimit formats an optional filter limit, unset limits are formatted as "-".
+func FormatLimit[T any](v *T) string {
+	if v == nil {
+		return "-"
+	}
+
+	return fmt.Sprint(*v)
+}
+
+// matches reports whether a listing should be used for pricing.
+func (f *ListingFilter) matches(l listing, now time.Time) bool {
+	if l.State != "listed" && l.State != "buffered" {
+		return false
+	}
+
+	if f == nil {
+		return true
+	}
+
+	if f.BuyNowOnly && l.Type != listingTypeBuyNow {
+		return false
+	}
+
+	if f.MaxFailedTrades != nil && l.Seller.Statistics.TotalFailedTrades > *f.MaxFailedTrades {
+		return false
+	}
+
+	if f.MinVerifiedTrades != nil && l.Seller.Statistics.TotalVerifiedTrades < *f.MinVerifiedTrades {
+		return false
+	}
+
+	if f.MaxListingAge != nil && now.Sub(l.CreatedAt) > *f.MaxListingAge {
+		return false
+	}
+
+	return true
+}
+
+const listingTypeBuyNow = "buy_now"
+
 // TODO: add additional parameters like float etc. to narrow down listings
-func GetMedianItemPrice(ctx context.Context, apiKey string, marketHashName string) (int, error) {
+func GetMedianItemPrice(
+	ctx context.Context,
+	apiKey string,
+	marketHashName string,
+	filter *ListingFilter,
+) (int, error) {
 	if ctx == nil {
 		return 0, ErrContextNil
 	}
@@ -35,6 +102,9 @@ func GetMedianItemPrice(ctx context.Context, apiKey string, marketHashName strin
 	q.Set("page", "0")
 	q.Set("sort_by", "lowest_price")
 	q.Set("market_hash_name", marketHashName)
+	if filter != nil && filter.BuyNowOnly {
+		q.Set("type", listingTypeBuyNow)
+	}
 	u.RawQuery = q.Encode()
 
 	var req *http.Request
@@ -58,8 +128,22 @@ func GetMedianItemPrice(ctx context.Context, apiKey string, marketHashName strin
 		return 0, fmt.Errorf("API returned non-OK status: %s", resp.Status)
 	}
 
+	var body []byte
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	archive.Record(ctx, archive.KindCSFloatListings, marketHashName, body)
+
+	return ParseMedianItemPrice(body, filter, time.Now())
+}
+
+// ParseMedianItemPrice calculates the median price of a raw listings response, e.g. from an archived run.
+// Listing ages are calculated relative to now.
+func ParseMedianItemPrice(body []byte, filter *ListingFilter, now time.Time) (int, error) {
 	var listingsResponse getListingsResponse
-	err = json.NewDecoder(resp.Body).Decode(&listingsResponse)
+	err := json.Unmarshal(body, &listingsResponse)
 	if err != nil {
 		return 0, fmt.Errorf("failed to decode response body: %w", err)
 	}
@@ -70,13 +154,13 @@ func GetMedianItemPrice(ctx context.Context, apiKey string, marketHashName strin
 
 	var prices []int
 	for _, listing := range listingsResponse.Data {
-		if listing.State == "listed" || listing.State == "buffered" {
+		if filter.matches(listing, now) {
 			prices = append(prices, listing.Price)
 		}
 	}
 
 	if len(prices) == 0 {
-		return 0, errors.New("no active listings found to calculate median price")
+		return 0, errors.New("no active listings matching the filter found to calculate median price")
 	}
 
 	sort.Ints(prices)
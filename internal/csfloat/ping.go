@@ -0,0 +1,57 @@
+package csfloat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Ping performs a minimal authenticated request against the CSFloat API and returns the response latency.
+func Ping(ctx context.Context, apiKey string) (time.Duration, error) {
+	if ctx == nil {
+		return 0, ErrContextNil
+	}
+
+	if apiKey == "" {
+		return 0, errors.New("apiKey cannot be empty")
+	}
+
+	u, err := url.Parse(getAllListingsURL)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("limit", "1")
+	u.RawQuery = q.Encode()
+
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	err = applyHeaders(req, apiKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to apply headers: %w", err)
+	}
+
+	start := time.Now()
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	latency := time.Since(start)
+
+	if resp.StatusCode != http.StatusOK {
+		return latency, fmt.Errorf("API returned non-OK status: %s", resp.Status)
+	}
+
+	return latency, nil
+}
@@ -0,0 +1,45 @@
+package csfloat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var ErrInvalidAPIKey = errors.New("api key was rejected by CSFloat")
+
+// ValidateAPIKey checks the API key against an endpoint which requires authentication.
+// The listings endpoint used for pricing also answers unauthenticated requests, so it cannot be used.
+func ValidateAPIKey(ctx context.Context, apiKey string) error {
+	if ctx == nil {
+		return ErrContextNil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, meURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	err = applyHeaders(req, apiKey)
+	if err != nil {
+		return fmt.Errorf("failed to apply headers: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrInvalidAPIKey
+	default:
+		return fmt.Errorf("API returned non-OK status: %s", resp.Status)
+	}
+}
+
+const meURL = "https://csfloat.com/api/v1/me"
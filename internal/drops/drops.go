@@ -0,0 +1,223 @@
+package drops
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devusSs/dropawp/internal/storage"
+)
+
+// Kind classifies an item which was added or removed between two runs.
+// Steam does not expose where an item came from, so the kind is a best guess.
+type Kind string
+
+const (
+	KindDrop     Kind = "drop"
+	KindPurchase Kind = "purchase"
+	KindTradeIn  Kind = "trade_in"
+	KindReturned Kind = "returned"
+	KindRemoved  Kind = "removed"
+)
+
+// Event is an item which was added or removed between two runs.
+// Value is the price in USD cents when the change was detected, or the last known price for removed items.
+type Event struct {
+	Kind           Kind      `json:"kind"`
+	Account        string    `json:"account"`
+	AssetID        string    `json:"asset_id"`
+	MarketHashName string    `json:"market_hash_name"`
+	Type           string    `json:"type"`
+	Rarity         string    `json:"rarity"`
+	Value          int       `json:"value"`
+	DetectedAt     time.Time `json:"detected_at"`
+}
+
+func (e Event) String() string {
+	return fmt.Sprintf(
+		"Event{Kind: %s, Account: %s, AssetID: %s, MarketHashName: %s, Type: %s, Rarity: %s, Value: %d, DetectedAt: %s}",
+		e.Kind,
+		e.Account,
+		e.AssetID,
+		e.MarketHashName,
+		e.Type,
+		e.Rarity,
+		e.Value,
+		e.DetectedAt.Format(time.RFC3339),
+	)
+}
+
+const (
+	// maxDropsPerWeek is the number of items a weekly care package grants.
+	maxDropsPerWeek = 2
+	// tradeUpInputs is the number of items consumed by a trade up contract.
+	tradeUpInputs = 10
+)
+
+// rarities are the weapon skin rarities in trade up order.
+var rarities = []string{
+	"Consumer Grade",
+	"Industrial Grade",
+	"Mil-Spec Grade",
+	"Restricted",
+	"Classified",
+	"Covert",
+}
+
+// dropRarities are the rarities weekly drops are assumed to have, alongside containers.
+var dropRarities = map[string]bool{
+	"Base Grade":       true,
+	"Consumer Grade":   true,
+	"Industrial Grade": true,
+	"Mil-Spec Grade":   true,
+}
+
+// Detect compares the assets of two consecutive runs per account.
+// Logged are the events of earlier runs, as returned by Read.
+//
+// Items are classified in the following order:
+//   - returned, if an item of the same name was removed from the same account before,
+//     e.g. it was put into and taken back out of a storage unit, which assigns a new asset id
+//   - trade in, if enough items of the next lower rarity were removed from the same account
+//   - drop, if it could be part of a weekly care package, at most two per account and week
+//   - purchase otherwise, which includes items received via trade
+func Detect(previous []storage.Asset, current []storage.Asset, logged []Event, detectedAt time.Time) []Event {
+	previousIDs := make(map[string]bool, len(previous))
+	for _, a := range previous {
+		previousIDs[assetKey(a)] = true
+	}
+
+	currentIDs := make(map[string]bool, len(current))
+	for _, a := range current {
+		currentIDs[assetKey(a)] = true
+	}
+
+	events := make([]Event, 0)
+	removedByRarity := make(map[string]map[string]int)
+	// Items removed in this and earlier runs may come back with a new asset id.
+	returnable := loggedRemovals(logged)
+	removedByName := make(map[string]int)
+
+	for _, a := range previous {
+		if currentIDs[assetKey(a)] {
+			continue
+		}
+
+		events = append(events, newEvent(KindRemoved, a, detectedAt))
+
+		if removedByRarity[a.Account] == nil {
+			removedByRarity[a.Account] = make(map[string]int)
+		}
+		removedByRarity[a.Account][a.Rarity]++
+
+		returnable[nameKey(a.Account, a.MarketHashName)]++
+		removedByName[nameKey(a.Account, a.MarketHashName)]++
+	}
+
+	drops := loggedDrops(logged, WeekStart(detectedAt))
+
+	for _, a := range current {
+		if previousIDs[assetKey(a)] {
+			continue
+		}
+
+		kind := KindPurchase
+		switch {
+		case returnable[nameKey(a.Account, a.MarketHashName)] > 0:
+			kind = KindReturned
+			returnable[nameKey(a.Account, a.MarketHashName)]--
+
+			// An item returned in the same run cannot be a trade up input as well.
+			if removedByName[nameKey(a.Account, a.MarketHashName)] > 0 {
+				removedByName[nameKey(a.Account, a.MarketHashName)]--
+				removedByRarity[a.Account][a.Rarity]--
+			}
+		case consumeTradeUpInputs(removedByRarity[a.Account], a.Rarity):
+			kind = KindTradeIn
+		case isDropCandidate(a) && drops[a.Account] < maxDropsPerWeek:
+			kind = KindDrop
+			drops[a.Account]++
+		}
+
+		events = append(events, newEvent(kind, a, detectedAt))
+	}
+
+	return events
+}
+
+func assetKey(a storage.Asset) string {
+	return a.Account + "_" + a.AssetID
+}
+
+func nameKey(account string, marketHashName string) string {
+	return account + "_" + marketHashName
+}
+
+// loggedDrops counts the drops per account logged since the start of the current week.
+func loggedDrops(logged []Event, weekStart time.Time) map[string]int {
+	drops := make(map[string]int)
+	for _, e := range logged {
+		if e.Kind == KindDrop && !e.DetectedAt.Before(weekStart) {
+			drops[e.Account]++
+		}
+	}
+
+	return drops
+}
+
+// loggedRemovals counts the removed items per account and name which have not been returned yet.
+func loggedRemovals(logged []Event) map[string]int {
+	removals := make(map[string]int)
+	for _, e := range logged {
+		switch e.Kind {
+		case KindRemoved:
+			removals[nameKey(e.Account, e.MarketHashName)]++
+		case KindReturned:
+			removals[nameKey(e.Account, e.MarketHashName)]--
+		case KindDrop, KindPurchase, KindTradeIn:
+		}
+	}
+
+	return removals
+}
+
+func newEvent(kind Kind, a storage.Asset, detectedAt time.Time) Event {
+	return Event{
+		Kind:           kind,
+		Account:        a.Account,
+		AssetID:        a.AssetID,
+		MarketHashName: a.MarketHashName,
+		Type:           a.Type,
+		Rarity:         a.Rarity,
+		Value:          a.Price,
+		DetectedAt:     detectedAt,
+	}
+}
+
+// consumeTradeUpInputs reports whether an item of the given rarity could be the result of a trade up
+// and removes the used inputs.
+func consumeTradeUpInputs(removed map[string]int, rarity string) bool {
+	for i := 1; i < len(rarities); i++ {
+		if rarities[i] != rarity {
+			continue
+		}
+
+		if removed[rarities[i-1]] < tradeUpInputs {
+			return false
+		}
+
+		removed[rarities[i-1]] -= tradeUpInputs
+
+		return true
+	}
+
+	return false
+}
+
+func isDropCandidate(a storage.Asset) bool {
+	if strings.HasPrefix(a.MarketHashName, "StatTrak™") || strings.HasPrefix(a.MarketHashName, "Souvenir") {
+		return false
+	}
+
+	return strings.Contains(a.Type, "Container") || dropRarities[a.Rarity]
+}
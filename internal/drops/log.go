@@ -0,0 +1,138 @@
+package drops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/devusSs/dropawp/internal/storage"
+)
+
+// Read returns all logged events of a project, oldest first.
+func Read(projectName string) ([]Event, error) {
+	path, err := logPath(projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []Event{}, nil
+		}
+
+		return nil, fmt.Errorf("failed to open drops log %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var events []Event
+	err = json.NewDecoder(f).Decode(&events)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode drops log %s: %w", path, err)
+	}
+
+	return events, nil
+}
+
+// Append adds events to the drops log of a project.
+func Append(projectName string, events []Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	logged, err := Read(projectName)
+	if err != nil {
+		return err
+	}
+
+	var path string
+	path, err = logPath(projectName)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create drops log %s: %w", path, err)
+	}
+	defer f.Close()
+
+	err = json.NewEncoder(f).Encode(append(logged, events...))
+	if err != nil {
+		return fmt.Errorf("failed to encode drops log: %w", err)
+	}
+
+	return nil
+}
+
+func logPath(projectName string) (string, error) {
+	storageDir, err := storage.Dir(projectName)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(storageDir, "drops.json"), nil
+}
+
+// Week sums up the drops of a weekly care package period.
+type Week struct {
+	Start time.Time `json:"start"`
+	Drops int       `json:"drops"`
+	Value int       `json:"value"`
+}
+
+func (w Week) String() string {
+	return fmt.Sprintf("Week{Start: %s, Drops: %d, Value: %d}", w.Start.Format(time.RFC3339), w.Drops, w.Value)
+}
+
+// weeklyReset is the weekday the weekly care package resets on, at midnight UTC.
+const weeklyReset = time.Wednesday
+
+// WeekStart returns the start of the weekly care package period t falls into.
+func WeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	const daysPerWeek = 7
+
+	offset := (int(start.Weekday()) - int(weeklyReset) + daysPerWeek) % daysPerWeek
+
+	return start.AddDate(0, 0, -offset)
+}
+
+// Weekly sums up drop events per week, oldest first.
+func Weekly(events []Event) []Week {
+	weeks := make(map[time.Time]*Week)
+
+	for _, e := range events {
+		if e.Kind != KindDrop {
+			continue
+		}
+
+		start := WeekStart(e.DetectedAt)
+
+		w, ok := weeks[start]
+		if !ok {
+			w = &Week{Start: start}
+			weeks[start] = w
+		}
+
+		w.Drops++
+		w.Value += e.Value
+	}
+
+	result := make([]Week, 0, len(weeks))
+	for _, w := range weeks {
+		result = append(result, *w)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Start.Before(result[j].Start)
+	})
+
+	return result
+}
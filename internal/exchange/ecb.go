@@ -0,0 +1,116 @@
+package exchange
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// ecbProvider reads rates in the European Central Bank reference rates XML format.
+// The source may be the official feed, any other URL serving the same format or a local file.
+type ecbProvider struct {
+	source string
+}
+
+func (p *ecbProvider) Name() ProviderName {
+	return ProviderECB
+}
+
+func (p *ecbProvider) Rates(ctx context.Context) (*Rates, error) {
+	if ctx == nil {
+		return nil, ErrContextNil
+	}
+
+	r, err := p.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var res ecbEnvelope
+	err = xml.NewDecoder(r).Decode(&res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode ecb rates: %w", err)
+	}
+
+	if len(res.Cube.Days) == 0 {
+		return nil, errors.New("ecb rates contain no days")
+	}
+
+	day := res.Cube.Days[0]
+
+	var date time.Time
+	date, err = time.Parse(time.DateOnly, day.Time)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date in ecb rates: %w", err)
+	}
+
+	rates := make(map[string]float64, len(day.Rates))
+	for _, rate := range day.Rates {
+		rates[rate.Currency] = rate.Rate
+	}
+
+	if len(rates) == 0 {
+		return nil, errors.New("ecb rates contain no rates")
+	}
+
+	return &Rates{
+		Base:  ecbBaseCurrency,
+		Date:  date,
+		Rates: rates,
+	}, nil
+}
+
+func (p *ecbProvider) open(ctx context.Context) (io.ReadCloser, error) {
+	if !strings.HasPrefix(p.source, "http://") && !strings.HasPrefix(p.source, "https://") {
+		f, err := os.Open(p.source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open ecb rates file: %w", err)
+		}
+
+		return f, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/xml")
+
+	var resp *http.Response
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
+
+const (
+	ecbDailyURL     = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+	ecbBaseCurrency = "EUR"
+)
+
+type ecbEnvelope struct {
+	Cube struct {
+		Days []struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string  `xml:"currency,attr"`
+				Rate     float64 `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
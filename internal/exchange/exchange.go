@@ -0,0 +1,146 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var ErrContextNil = errors.New("context cannot be nil")
+
+type ProviderName string
+
+const (
+	ProviderStatic ProviderName = "static"
+	ProviderECB    ProviderName = "ecb"
+)
+
+// Provider returns exchange rates relative to a base currency.
+type Provider interface {
+	Name() ProviderName
+	Rates(ctx context.Context) (*Rates, error)
+}
+
+// NewProvider returns the provider with the given name.
+// The source is a file path or URL depending on the provider.
+func NewProvider(name ProviderName, source string) (Provider, error) {
+	switch name {
+	case ProviderStatic:
+		if source == "" {
+			return nil, errors.New("static provider requires a rates file")
+		}
+
+		return &staticProvider{file: source}, nil
+	case ProviderECB:
+		if source == "" {
+			source = ecbDailyURL
+		}
+
+		return &ecbProvider{source: source}, nil
+	default:
+		return nil, fmt.Errorf("unknown exchange rate provider: %s", name)
+	}
+}
+
+type Rates struct {
+	Base  string             `json:"base"`
+	Date  time.Time          `json:"date"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+func (r *Rates) String() string {
+	return fmt.Sprintf("%+v", *r)
+}
+
+// Conversion returns the conversion between two currencies using cross rates via the base currency.
+func (r *Rates) Conversion(from string, to string) (*Conversion, error) {
+	fromRate, err := r.rate(from)
+	if err != nil {
+		return nil, err
+	}
+
+	var toRate float64
+	toRate, err = r.rate(to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Conversion{
+		From: from,
+		To:   to,
+		Rate: toRate / fromRate,
+		Date: r.Date,
+	}, nil
+}
+
+func (r *Rates) rate(currency string) (float64, error) {
+	if currency == r.Base {
+		return 1, nil
+	}
+
+	rate, ok := r.Rates[currency]
+	if !ok {
+		return 0, fmt.Errorf("no exchange rate for currency %s", currency)
+	}
+
+	if rate <= 0 {
+		return 0, fmt.Errorf("invalid exchange rate for currency %s: %f", currency, rate)
+	}
+
+	return rate, nil
+}
+
+type Conversion struct {
+	From     string       `json:"from"`
+	To       string       `json:"to"`
+	Rate     float64      `json:"rate"`
+	Date     time.Time    `json:"date"`
+	Provider ProviderName `json:"provider"`
+}
+
+func (c *Conversion) String() string {
+	return fmt.Sprintf("%+v", *c)
+}
+
+// Identity returns a conversion which does not change any amounts.
+func Identity(currency string) *Conversion {
+	return &Conversion{
+		From: currency,
+		To:   currency,
+		Rate: 1,
+		Date: time.Now(),
+	}
+}
+
+// Convert converts an amount in the smallest currency unit (e.g. cents).
+func (c *Conversion) Convert(amount int) int {
+	return int(math.Round(float64(amount) * c.Rate))
+}
+
+// GetConversion fetches the current rates from the provider and returns the conversion between both currencies.
+func GetConversion(ctx context.Context, p Provider, from string, to string) (*Conversion, error) {
+	if ctx == nil {
+		return nil, ErrContextNil
+	}
+
+	if p == nil {
+		return nil, errors.New("provider cannot be nil")
+	}
+
+	rates, err := p.Rates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rates from provider %s: %w", p.Name(), err)
+	}
+
+	var c *Conversion
+	c, err = rates.Conversion(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversion %s -> %s: %w", from, to, err)
+	}
+
+	c.Provider = p.Name()
+
+	return c, nil
+}
@@ -0,0 +1,66 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// staticProvider reads rates from a local JSON file, e.g.
+// {"base": "USD", "date": "2025-01-31", "rates": {"EUR": 0.96}}.
+type staticProvider struct {
+	file string
+}
+
+func (p *staticProvider) Name() ProviderName {
+	return ProviderStatic
+}
+
+func (p *staticProvider) Rates(ctx context.Context) (*Rates, error) {
+	if ctx == nil {
+		return nil, ErrContextNil
+	}
+
+	f, err := os.Open(p.file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rates file: %w", err)
+	}
+	defer f.Close()
+
+	var res staticRatesFile
+	err = json.NewDecoder(f).Decode(&res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode rates file: %w", err)
+	}
+
+	if res.Base == "" {
+		return nil, errors.New("rates file is missing base currency")
+	}
+
+	if len(res.Rates) == 0 {
+		return nil, errors.New("rates file contains no rates")
+	}
+
+	date := time.Now()
+	if res.Date != "" {
+		date, err = time.Parse(time.DateOnly, res.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date in rates file: %w", err)
+		}
+	}
+
+	return &Rates{
+		Base:  res.Base,
+		Date:  date,
+		Rates: res.Rates,
+	}, nil
+}
+
+type staticRatesFile struct {
+	Base  string             `json:"base"`
+	Date  string             `json:"date"`
+	Rates map[string]float64 `json:"rates"`
+}
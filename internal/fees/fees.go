@@ -0,0 +1,64 @@
+package fees
+
+import (
+	"fmt"
+	"math"
+)
+
+type Provider string
+
+const (
+	ProviderSteam   Provider = "steam"
+	ProviderCSFloat Provider = "csfloat"
+)
+
+// Schedule describes the seller fee of a marketplace.
+// Percent is applied to the gross price, Minimum is the lowest fee in the smallest currency unit (e.g. cents).
+type Schedule struct {
+	Percent float64 `json:"percent"`
+	Minimum int     `json:"minimum"`
+}
+
+func (s Schedule) String() string {
+	return fmt.Sprintf("Schedule{Percent: %.2f, Minimum: %d}", s.Percent, s.Minimum)
+}
+
+const percentFactor = 100
+
+// Fee returns the fee charged when selling an item at the given gross price.
+// The fee never exceeds the price itself.
+func (s Schedule) Fee(price int) int {
+	if price <= 0 {
+		return 0
+	}
+
+	fee := max(int(math.Round(float64(price)*s.Percent/percentFactor)), s.Minimum)
+
+	return min(fee, price)
+}
+
+// Net returns the proceeds when selling an item at the given gross price.
+func (s Schedule) Net(price int) int {
+	return price - s.Fee(price)
+}
+
+// DefaultSchedules are the known seller fees at the time of writing.
+// They can be overridden per provider in the config.
+var DefaultSchedules = map[Provider]Schedule{
+	ProviderSteam:   {Percent: 15, Minimum: 1},
+	ProviderCSFloat: {Percent: 2, Minimum: 0},
+}
+
+// GetSchedule returns the schedule for the provider, preferring overrides over the defaults.
+func GetSchedule(provider Provider, overrides map[Provider]Schedule) (Schedule, error) {
+	if s, ok := overrides[provider]; ok {
+		return s, nil
+	}
+
+	s, ok := DefaultSchedules[provider]
+	if !ok {
+		return Schedule{}, fmt.Errorf("no fee schedule for provider: %s", provider)
+	}
+
+	return s, nil
+}
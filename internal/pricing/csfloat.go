@@ -0,0 +1,70 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/devusSs/dropawp/internal/archive"
+	"github.com/devusSs/dropawp/internal/csfloat"
+	"github.com/devusSs/dropawp/internal/steam"
+)
+
+type csfloatProvider struct {
+	apiKey string
+	filter *csfloat.ListingFilter
+}
+
+// NewCSFloat prices CS2 items by the median of filtered CSFloat listings
+// and uses the highest buy order as liquidation price.
+func NewCSFloat(apiKey string, filter *csfloat.ListingFilter) Provider {
+	return &csfloatProvider{apiKey: apiKey, filter: filter}
+}
+
+func (p *csfloatProvider) Name() string {
+	return ProviderCSFloat
+}
+
+func (p *csfloatProvider) SupportsApp(appID int) bool {
+	return appID == steam.AppCS2.ID
+}
+
+// CacheKey includes the filter so projects with different filters do not share median prices.
+func (p *csfloatProvider) CacheKey() string {
+	if p.filter == nil {
+		return "csfloat_listings"
+	}
+
+	return fmt.Sprintf(
+		"csfloat_listings_%t_%s_%s_%s",
+		p.filter.BuyNowOnly,
+		csfloat.FormatLimit(p.filter.MaxFailedTrades),
+		csfloat.FormatLimit(p.filter.MinVerifiedTrades),
+		csfloat.FormatLimit(p.filter.MaxListingAge),
+	)
+}
+
+func (p *csfloatProvider) Price(ctx context.Context, marketHashName string) (int, error) {
+	return csfloat.GetMedianItemPrice(ctx, p.apiKey, marketHashName, p.filter)
+}
+
+func (p *csfloatProvider) LiquidationPrice(ctx context.Context, marketHashName string) (int, error) {
+	return csfloat.GetHighestBuyOrderPrice(ctx, p.apiKey, marketHashName)
+}
+
+func (p *csfloatProvider) ArchivedPrice(a *archive.Archive, marketHashName string) (int, error) {
+	r, err := lastArchived(a, archive.KindCSFloatListings, marketHashName)
+	if err != nil {
+		return 0, err
+	}
+
+	return csfloat.ParseMedianItemPrice(r.Body, p.filter, r.FetchedAt)
+}
+
+func (p *csfloatProvider) ArchivedLiquidationPrice(a *archive.Archive, marketHashName string) (int, error) {
+	r, err := lastArchived(a, archive.KindCSFloatBuyOrders, marketHashName)
+	if err != nil {
+		return 0, err
+	}
+
+	return csfloat.ParseHighestBuyOrderPrice(r.Body)
+}
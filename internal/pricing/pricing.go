@@ -0,0 +1,103 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/devusSs/dropawp/internal/archive"
+	"github.com/devusSs/dropawp/internal/cache"
+	"github.com/devusSs/dropawp/internal/steam"
+)
+
+var (
+	ErrNotSupported = errors.New("not supported by pricing provider")
+	// ErrNotArchived is returned if an archive contains no response for an item,
+	// e.g. because its price was taken from the cache.
+	ErrNotArchived = errors.New("no archived response for item")
+)
+
+const (
+	ProviderCSFloat     = "csfloat"
+	ProviderSteamMarket = "steam"
+)
+
+// Provider prices items of the apps it supports. All prices are in USD cents.
+type Provider interface {
+	Name() string
+	SupportsApp(appID int) bool
+	// CacheKey identifies prices of this provider and its settings in the price cache.
+	CacheKey() string
+	// Price returns the market value of an item.
+	Price(ctx context.Context, marketHashName string) (int, error)
+	// LiquidationPrice returns the price an item could be sold for instantly.
+	LiquidationPrice(ctx context.Context, marketHashName string) (int, error)
+	// ArchivedPrice parses the market value of an item from the raw responses of an archived run.
+	ArchivedPrice(a *archive.Archive, marketHashName string) (int, error)
+	// ArchivedLiquidationPrice parses the liquidation price of an item from the raw responses of an archived run.
+	ArchivedLiquidationPrice(a *archive.Archive, marketHashName string) (int, error)
+}
+
+// lastArchived returns the latest response of a kind for an item.
+func lastArchived(a *archive.Archive, kind archive.Kind, marketHashName string) (archive.Response, error) {
+	responses := a.Find(kind, marketHashName)
+	if len(responses) == 0 {
+		return archive.Response{}, ErrNotArchived
+	}
+
+	return responses[len(responses)-1], nil
+}
+
+// DefaultProviderName returns the provider used for an app if none is configured.
+func DefaultProviderName(appID int) string {
+	if appID == steam.AppCS2.ID {
+		return ProviderCSFloat
+	}
+
+	return ProviderSteamMarket
+}
+
+type cachedProvider struct {
+	Provider
+
+	cache *cache.PriceCache
+}
+
+// WithCache returns a provider which looks up prices in the cache before querying p
+// and stores all successfully queried prices.
+func WithCache(p Provider, c *cache.PriceCache) Provider {
+	return &cachedProvider{Provider: p, cache: c}
+}
+
+func (p *cachedProvider) Price(ctx context.Context, marketHashName string) (int, error) {
+	return p.cached(p.CacheKey(), marketHashName, p.cache.Get, func() (int, error) {
+		return p.Provider.Price(ctx, marketHashName)
+	})
+}
+
+// LiquidationPrice lookups are not counted in the cache stats, every item is already counted by Price.
+func (p *cachedProvider) LiquidationPrice(ctx context.Context, marketHashName string) (int, error) {
+	return p.cached(p.CacheKey()+"_liquidation", marketHashName, p.cache.Lookup, func() (int, error) {
+		return p.Provider.LiquidationPrice(ctx, marketHashName)
+	})
+}
+
+func (p *cachedProvider) cached(
+	key string,
+	marketHashName string,
+	lookup func(provider string, marketHashName string) (int, bool),
+	query func() (int, error),
+) (int, error) {
+	price, ok := lookup(key, marketHashName)
+	if ok {
+		return price, nil
+	}
+
+	price, err := query()
+	if err != nil {
+		return 0, err
+	}
+
+	p.cache.Set(key, marketHashName, price)
+
+	return price, nil
+}
@@ -0,0 +1,149 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/devusSs/dropawp/internal/archive"
+	"github.com/devusSs/dropawp/internal/steam"
+)
+
+// The price overview rate limits after roughly 20 requests per minute and IP address.
+const (
+	steamMarketMaxConcurrent   = 2
+	steamMarketRequestInterval = 3 * time.Second
+	steamMarketMaxRetries      = 3
+	steamMarketRetryBackoff    = 30 * time.Second
+)
+
+type steamMarketProvider struct {
+	appID int
+
+	// slots limits the number of concurrent requests.
+	slots chan struct{}
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// NewSteamMarket prices items of any app by the Steam Community Market median price.
+// The price overview does not include buy orders, so there is no liquidation price.
+// Requests are throttled and retried with backoff if Steam rate limits them.
+func NewSteamMarket(appID int) Provider {
+	return &steamMarketProvider{
+		appID: appID,
+		slots: make(chan struct{}, steamMarketMaxConcurrent),
+	}
+}
+
+func (p *steamMarketProvider) Name() string {
+	return ProviderSteamMarket
+}
+
+func (p *steamMarketProvider) SupportsApp(appID int) bool {
+	return appID == p.appID
+}
+
+func (p *steamMarketProvider) CacheKey() string {
+	return fmt.Sprintf("steam_market_%d", p.appID)
+}
+
+func (p *steamMarketProvider) Price(ctx context.Context, marketHashName string) (int, error) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+	defer func() { <-p.slots }()
+
+	backoff := steamMarketRetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := p.wait(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		var overview *steam.MarketPriceOverview
+		overview, err = steam.GetMarketPriceOverview(ctx, p.appID, marketHashName)
+		if errors.Is(err, steam.ErrMarketRateLimited) && attempt < steamMarketMaxRetries {
+			p.pause(backoff)
+			backoff *= 2
+
+			continue
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		return overviewPrice(overview)
+	}
+}
+
+// wait blocks until the next request may be sent, requests are spaced by steamMarketRequestInterval.
+func (p *steamMarketProvider) wait(ctx context.Context) error {
+	p.mu.Lock()
+	at := p.next
+	if now := time.Now(); at.Before(now) {
+		at = now
+	}
+	p.next = at.Add(steamMarketRequestInterval)
+	p.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pause delays all following requests, the rate limit applies to all of them.
+func (p *steamMarketProvider) pause(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if resume := time.Now().Add(d); p.next.Before(resume) {
+		p.next = resume
+	}
+}
+
+func overviewPrice(overview *steam.MarketPriceOverview) (int, error) {
+	if overview.MedianPrice > 0 {
+		return overview.MedianPrice, nil
+	}
+
+	if overview.LowestPrice > 0 {
+		return overview.LowestPrice, nil
+	}
+
+	return 0, errors.New("no market price found for the given market hash name")
+}
+
+func (p *steamMarketProvider) LiquidationPrice(_ context.Context, _ string) (int, error) {
+	return 0, ErrNotSupported
+}
+
+func (p *steamMarketProvider) ArchivedPrice(a *archive.Archive, marketHashName string) (int, error) {
+	r, err := lastArchived(a, archive.KindSteamMarketPrice, marketHashName)
+	if err != nil {
+		return 0, err
+	}
+
+	overview, err := steam.ParseMarketPriceOverview(r.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	return overviewPrice(overview)
+}
+
+func (p *steamMarketProvider) ArchivedLiquidationPrice(_ *archive.Archive, _ string) (int, error) {
+	return 0, ErrNotSupported
+}
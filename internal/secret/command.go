@@ -0,0 +1,112 @@
+package secret
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Placeholders in command templates. ProjectPlaceholder is replaced with the namespace of the secret,
+// which is empty for global secrets.
+const (
+	KeyPlaceholder     = "{key}"
+	ProjectPlaceholder = "{project}"
+)
+
+// DefaultCommandTimeout is the time a secret command may run if no timeout is configured.
+const DefaultCommandTimeout = 10 * time.Second
+
+// commandStore obtains secrets by running a command, e.g. of a password manager CLI.
+// Outputs are cached for the lifetime of the store, so every command runs at most once per run.
+type commandStore struct {
+	template string
+	timeout  time.Duration
+
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+func newCommandStore(template string, timeout time.Duration) (*commandStore, error) {
+	if strings.TrimSpace(template) == "" {
+		return nil, errors.New("secret command cannot be empty")
+	}
+
+	if timeout <= 0 {
+		timeout = DefaultCommandTimeout
+	}
+
+	return &commandStore{
+		template: template,
+		timeout:  timeout,
+		cache:    make(map[string]string),
+	}, nil
+}
+
+func (s *commandStore) Name() string {
+	return BackendCommand
+}
+
+func (s *commandStore) Get(namespace string, key Key) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	command := strings.NewReplacer(KeyPlaceholder, string(key), ProjectPlaceholder, namespace).Replace(s.template)
+
+	// Templates without ProjectPlaceholder resolve to the same command for every namespace.
+	value, ok := s.cache[command]
+	if ok {
+		return value, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := shellCommand(ctx, command)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Children of the shell may keep the output open after it was killed.
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "", fmt.Errorf("secret command for %s timed out after %s", key, s.timeout)
+	}
+
+	// Password managers exit with an error for missing entries. A missing project secret
+	// falls back to the global one, so it must not be reported as failure.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && namespace != GlobalNamespace && strings.TrimSpace(stdout.String()) == "" {
+		return "", fmt.Errorf("%w: secret command for %s exited with code %d: %s",
+			ErrNotFound, key, exitErr.ExitCode(), strings.TrimSpace(stderr.String()))
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("secret command for %s failed: %w: %s", key, err, strings.TrimSpace(stderr.String()))
+	}
+
+	// Password managers like pass may print additional lines after the secret.
+	value, _, _ = strings.Cut(strings.TrimSpace(stdout.String()), "\n")
+	value = strings.TrimSpace(value)
+
+	if value == "" {
+		return "", ErrNotFound
+	}
+
+	s.cache[command] = value
+
+	return value, nil
+}
+
+func (s *commandStore) Set(_ string, key Key, _ string) error {
+	return fmt.Errorf("%w: store %s in your secret manager instead", ErrReadOnly, key)
+}
+
+func (s *commandStore) DeleteAll(_ string) error {
+	return fmt.Errorf("%w: delete the secrets in your secret manager instead", ErrReadOnly)
+}
@@ -0,0 +1,43 @@
+package secret
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const envPrefix = "DROPAWP_"
+
+// EnvName returns the environment variable the env backend reads a secret from,
+// e.g. DROPAWP_STEAM_API_KEY globally or DROPAWP_MYPROJECT_STEAM_API_KEY for a project.
+func EnvName(namespace string, key Key) string {
+	if namespace == GlobalNamespace {
+		return envPrefix + strings.ToUpper(string(key))
+	}
+
+	return envPrefix + strings.ToUpper(namespace) + "_" + strings.ToUpper(string(key))
+}
+
+// envStore reads secrets from environment variables. It cannot store secrets.
+type envStore struct{}
+
+func (s *envStore) Name() string {
+	return BackendEnv
+}
+
+func (s *envStore) Get(namespace string, key Key) (string, error) {
+	value, ok := os.LookupEnv(EnvName(namespace, key))
+	if !ok || value == "" {
+		return "", ErrNotFound
+	}
+
+	return value, nil
+}
+
+func (s *envStore) Set(namespace string, key Key, _ string) error {
+	return fmt.Errorf("%w: set %s instead", ErrReadOnly, EnvName(namespace, key))
+}
+
+func (s *envStore) DeleteAll(_ string) error {
+	return fmt.Errorf("%w: unset the %s* environment variables instead", ErrReadOnly, envPrefix)
+}
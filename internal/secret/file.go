@@ -0,0 +1,277 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// PassphraseEnv is the environment variable the file backend reads its passphrase from.
+// The passphrase is prompted for if it is not set.
+//
+//nolint:gosec // This is the name of the variable, not a passphrase.
+const PassphraseEnv = "DROPAWP_SECRETS_PASSPHRASE"
+
+const (
+	fileVersion    = 1
+	fileSaltLength = 16
+	fileKeyLength  = 32
+	// fileIterations follows the OWASP recommendation for PBKDF2-HMAC-SHA256.
+	fileIterations = 600000
+)
+
+// fileStore keeps secrets in a file encrypted with AES-256-GCM.
+// The key is derived from a passphrase using PBKDF2.
+// Project secrets are stored as "<project>/<key>", global secrets by their key.
+type fileStore struct {
+	path string
+
+	mu         sync.Mutex
+	passphrase string
+	secrets    map[string]string
+}
+
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+func newFileStore(path string) (*fileStore, error) {
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user home directory: %w", err)
+		}
+
+		path = filepath.Join(home, ".dropawp", "secrets", "secrets.enc")
+	}
+
+	return &fileStore{path: path}, nil
+}
+
+func (s *fileStore) Name() string {
+	return BackendFile
+}
+
+func (s *fileStore) Get(namespace string, key Key) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.load()
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := s.secrets[fileEntryName(namespace, key)]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return value, nil
+}
+
+func (s *fileStore) Set(namespace string, key Key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.load()
+	if err != nil {
+		return err
+	}
+
+	s.secrets[fileEntryName(namespace, key)] = value
+
+	return s.save()
+}
+
+func (s *fileStore) DeleteAll(namespace string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.load()
+	if err != nil {
+		return err
+	}
+
+	for name := range s.secrets {
+		entryNamespace, _, found := strings.Cut(name, "/")
+		if !found {
+			entryNamespace = GlobalNamespace
+		}
+
+		if entryNamespace == namespace {
+			delete(s.secrets, name)
+		}
+	}
+
+	if len(s.secrets) > 0 {
+		return s.save()
+	}
+
+	err = os.Remove(s.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete secrets file %s: %w", s.path, err)
+	}
+
+	return nil
+}
+
+func fileEntryName(namespace string, key Key) string {
+	if namespace == GlobalNamespace {
+		return string(key)
+	}
+
+	return namespace + "/" + string(key)
+}
+
+func (s *fileStore) load() error {
+	if s.secrets != nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			s.secrets = make(map[string]string)
+			return nil
+		}
+
+		return fmt.Errorf("failed to read secrets file %s: %w", s.path, err)
+	}
+
+	var f encryptedFile
+	err = json.Unmarshal(data, &f)
+	if err != nil {
+		return fmt.Errorf("failed to decode secrets file %s: %w", s.path, err)
+	}
+
+	if f.Version != fileVersion {
+		return fmt.Errorf("unsupported secrets file version %d", f.Version)
+	}
+
+	var gcm cipher.AEAD
+	gcm, err = s.cipher(f.Salt, f.Iterations)
+	if err != nil {
+		return err
+	}
+
+	var plain []byte
+	plain, err = gcm.Open(nil, f.Nonce, f.Data, nil)
+	if err != nil {
+		return errors.New("failed to decrypt secrets file, wrong passphrase?")
+	}
+
+	secrets := make(map[string]string)
+	err = json.Unmarshal(plain, &secrets)
+	if err != nil {
+		return fmt.Errorf("failed to decode secrets: %w", err)
+	}
+
+	s.secrets = secrets
+
+	return nil
+}
+
+func (s *fileStore) save() error {
+	plain, err := json.Marshal(s.secrets)
+	if err != nil {
+		return fmt.Errorf("failed to encode secrets: %w", err)
+	}
+
+	f := encryptedFile{
+		Version:    fileVersion,
+		Iterations: fileIterations,
+		Salt:       make([]byte, fileSaltLength),
+	}
+
+	_, err = rand.Read(f.Salt)
+	if err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	var gcm cipher.AEAD
+	gcm, err = s.cipher(f.Salt, f.Iterations)
+	if err != nil {
+		return err
+	}
+
+	f.Nonce = make([]byte, gcm.NonceSize())
+	_, err = rand.Read(f.Nonce)
+	if err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	f.Data = gcm.Seal(nil, f.Nonce, plain, nil)
+
+	var data []byte
+	data, err = json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("failed to encode secrets file: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(s.path), 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create secrets directory: %w", err)
+	}
+
+	err = os.WriteFile(s.path, data, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write secrets file %s: %w", s.path, err)
+	}
+
+	return nil
+}
+
+func (s *fileStore) cipher(salt []byte, iterations int) (cipher.AEAD, error) {
+	passphrase, err := s.getPassphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, fileKeyLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func (s *fileStore) getPassphrase() (string, error) {
+	if s.passphrase != "" {
+		return s.passphrase, nil
+	}
+
+	passphrase := os.Getenv(PassphraseEnv)
+	if passphrase == "" {
+		var err error
+		passphrase, err = GetInput("Enter passphrase for secrets file " + s.path)
+		if err != nil {
+			return "", fmt.Errorf("failed to get passphrase, set %s on headless systems: %w", PassphraseEnv, err)
+		}
+	}
+
+	if passphrase == "" {
+		return "", errors.New("passphrase cannot be empty")
+	}
+
+	s.passphrase = passphrase
+
+	return passphrase, nil
+}
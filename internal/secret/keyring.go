@@ -0,0 +1,42 @@
+package secret
+
+import (
+	"errors"
+
+	"github.com/zalando/go-keyring"
+)
+
+const keyringService = "dropawp"
+
+// keyringStore uses the system password store.
+// Global secrets use the service "dropawp", project secrets "dropawp/<project>".
+type keyringStore struct{}
+
+func (s *keyringStore) Name() string {
+	return BackendKeyring
+}
+
+func (s *keyringStore) Get(namespace string, key Key) (string, error) {
+	value, err := keyring.Get(keyringServiceName(namespace), string(key))
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrNotFound
+	}
+
+	return value, err
+}
+
+func (s *keyringStore) Set(namespace string, key Key, value string) error {
+	return keyring.Set(keyringServiceName(namespace), string(key), value)
+}
+
+func (s *keyringStore) DeleteAll(namespace string) error {
+	return keyring.DeleteAll(keyringServiceName(namespace))
+}
+
+func keyringServiceName(namespace string) string {
+	if namespace == GlobalNamespace {
+		return keyringService
+	}
+
+	return keyringService + "/" + namespace
+}
@@ -1,11 +1,12 @@
 package secret
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
 
-	"github.com/zalando/go-keyring"
 	"golang.org/x/term"
 )
 
@@ -38,10 +39,75 @@ const (
 	CSFloatAPIKey Key = "csfloat_api_key"
 )
 
+// Keys returns all secrets used by dropawp.
+func Keys() []Key {
+	return []Key{SteamAPIKey, CSFloatAPIKey}
+}
+
+// ParseKey returns the key with the given name.
+func ParseKey(name string) (Key, error) {
+	for _, key := range Keys() {
+		if string(key) == name {
+			return key, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown secret %q, expected %s or %s", name, SteamAPIKey, CSFloatAPIKey)
+}
+
+const maskVisibleChars = 4
+
+// Mask hides all but the last four characters of a secret value.
+// The length of the value is not revealed.
+func Mask(value string) string {
+	if len(value) <= maskVisibleChars*2 {
+		return "****"
+	}
+
+	return "****" + value[len(value)-maskVisibleChars:]
+}
+
+const fingerprintLength = 8
+
+// Fingerprint returns a short hash of a secret value to compare values without revealing them.
+func Fingerprint(value string) string {
+	sum := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(sum[:])[:fingerprintLength]
+}
+
+// GlobalNamespace holds the secrets shared by all projects.
+// Projects inherit them unless they set their own.
+const GlobalNamespace = ""
+
+var namespace = GlobalNamespace
+
+// UseNamespace sets the namespace, usually the project name, used by Exists, Load, Save and DeleteAll.
+func UseNamespace(ns string) {
+	namespace = ns
+}
+
+// Namespace returns the namespace used by Exists, Load, Save and DeleteAll.
+func Namespace() string {
+	return namespace
+}
+
+// lookup returns a secret and the namespace it was found in, falling back to the global namespace.
+func lookup(key Key) (string, string, error) {
+	value, err := store.Get(namespace, key)
+	if err == nil || !errors.Is(err, ErrNotFound) || namespace == GlobalNamespace {
+		return value, namespace, err
+	}
+
+	value, err = store.Get(GlobalNamespace, key)
+
+	return value, GlobalNamespace, err
+}
+
 func Exists(key Key) (bool, error) {
-	_, err := keyring.Get("dropawp", string(key))
+	_, _, err := lookup(key)
 	if err != nil {
-		if errors.Is(err, keyring.ErrNotFound) {
+		if errors.Is(err, ErrNotFound) {
 			return false, nil
 		}
 
@@ -52,9 +118,9 @@ func Exists(key Key) (bool, error) {
 }
 
 func Load(key Key) (string, error) {
-	value, err := keyring.Get("dropawp", string(key))
+	value, _, err := lookup(key)
 	if err != nil {
-		if errors.Is(err, keyring.ErrNotFound) {
+		if errors.Is(err, ErrNotFound) {
 			return "", fmt.Errorf("key not found: %s", key)
 		}
 
@@ -64,12 +130,23 @@ func Load(key Key) (string, error) {
 	return value, nil
 }
 
+// Source returns the namespace a secret is inherited from or set in.
+func Source(key Key) (string, error) {
+	_, ns, err := lookup(key)
+	if err != nil {
+		return "", err
+	}
+
+	return ns, nil
+}
+
+// Save stores a secret in the current namespace.
 func Save(key Key, value string) error {
 	if value == "" {
 		return errors.New("value cannot be empty")
 	}
 
-	err := keyring.Set("dropawp", string(key), value)
+	err := store.Set(namespace, key, value)
 	if err != nil {
 		return fmt.Errorf("failed to save key: %w", err)
 	}
@@ -77,8 +154,9 @@ func Save(key Key, value string) error {
 	return nil
 }
 
+// DeleteAll deletes the secrets of the current namespace. Inherited global secrets are kept.
 func DeleteAll() error {
-	err := keyring.DeleteAll("dropawp")
+	err := store.DeleteAll(namespace)
 	if err != nil {
 		return fmt.Errorf("failed to delete all keys: %w", err)
 	}
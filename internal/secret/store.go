@@ -0,0 +1,79 @@
+package secret
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrNotFound = errors.New("secret not found")
+	ErrReadOnly = errors.New("secret backend is read-only")
+)
+
+// Store is a backend secrets are stored in. Secrets are separated by namespace,
+// GlobalNamespace holds the secrets shared by all projects.
+type Store interface {
+	Name() string
+	// Get returns ErrNotFound if the secret is not set in the namespace.
+	Get(namespace string, key Key) (string, error)
+	Set(namespace string, key Key, value string) error
+	// DeleteAll deletes all secrets of a namespace.
+	DeleteAll(namespace string) error
+}
+
+const (
+	BackendKeyring = "keyring"
+	BackendEnv     = "env"
+	BackendFile    = "file"
+	BackendCommand = "command"
+)
+
+// Options configure the backends. File is only used by the file backend
+// and defaults to ~/.dropawp/secrets/secrets.enc.
+// Command is the command template of the command backend, KeyPlaceholder is replaced with the secret key
+// and ProjectPlaceholder with the namespace.
+type Options struct {
+	File           string
+	Command        string
+	CommandTimeout time.Duration
+}
+
+// NewStore returns the store for a backend, the keyring is used if backend is empty.
+func NewStore(backend string, opts Options) (Store, error) {
+	switch backend {
+	case "", BackendKeyring:
+		return &keyringStore{}, nil
+	case BackendEnv:
+		return &envStore{}, nil
+	case BackendFile:
+		return newFileStore(opts.File)
+	case BackendCommand:
+		return newCommandStore(opts.Command, opts.CommandTimeout)
+	default:
+		return nil, fmt.Errorf(
+			"unknown secret backend '%s', expected %s, %s, %s or %s",
+			backend,
+			BackendKeyring,
+			BackendEnv,
+			BackendFile,
+			BackendCommand,
+		)
+	}
+}
+
+var store Store = &keyringStore{}
+
+// Use sets the store used by Exists, Load, Save and DeleteAll.
+func Use(s Store) {
+	if s == nil {
+		return
+	}
+
+	store = s
+}
+
+// Current returns the store used by Exists, Load, Save and DeleteAll.
+func Current() Store {
+	return store
+}
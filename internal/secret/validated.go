@@ -0,0 +1,99 @@
+package secret
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LastValidated returns when a secret of the current namespace, or the inherited global secret,
+// was last validated successfully. The time is zero if it was never validated.
+func LastValidated(key Key) (time.Time, error) {
+	validated, err := readValidated()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	ns, err := Source(key)
+	if err != nil {
+		ns = namespace
+	}
+
+	return validated[fileEntryName(ns, key)], nil
+}
+
+// MarkValidated records that the secret used by the current namespace was validated successfully at t.
+func MarkValidated(key Key, t time.Time) error {
+	validated, err := readValidated()
+	if err != nil {
+		return err
+	}
+
+	ns, err := Source(key)
+	if err != nil {
+		ns = namespace
+	}
+
+	validated[fileEntryName(ns, key)] = t
+
+	path, err := validatedPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(validated)
+	if err != nil {
+		return fmt.Errorf("failed to encode validation times: %w", err)
+	}
+
+	err = os.WriteFile(path, data, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write validation times %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func readValidated() (map[string]time.Time, error) {
+	path, err := validatedPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return make(map[string]time.Time), nil
+		}
+
+		return nil, fmt.Errorf("failed to read validation times %s: %w", path, err)
+	}
+
+	validated := make(map[string]time.Time)
+	err = json.Unmarshal(data, &validated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode validation times %s: %w", path, err)
+	}
+
+	return validated, nil
+}
+
+// validatedPath is not tied to a backend since only the time of validation is stored.
+func validatedPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	dir := filepath.Join(home, ".dropawp", "secrets")
+
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return "", fmt.Errorf("failed to create secrets directory %s: %w", dir, err)
+	}
+
+	return filepath.Join(dir, "validated.json"), nil
+}
@@ -0,0 +1,126 @@
+package steam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	EconomyBanNone      = "none"
+	EconomyBanProbation = "probation"
+	EconomyBanBanned    = "banned"
+)
+
+type PlayerBans struct {
+	SteamID64        uint64 `json:"steam_id_64"`
+	CommunityBanned  bool   `json:"community_banned"`
+	VACBanned        bool   `json:"vac_banned"`
+	NumberOfVACBans  int    `json:"number_of_vac_bans"`
+	DaysSinceLastBan int    `json:"days_since_last_ban"`
+	NumberOfGameBans int    `json:"number_of_game_bans"`
+	EconomyBan       string `json:"economy_ban"`
+}
+
+func (b *PlayerBans) String() string {
+	return fmt.Sprintf("%+v", *b)
+}
+
+// TradeBanned reports whether the account is currently banned from trading.
+func (b *PlayerBans) TradeBanned() bool {
+	return b.EconomyBan == EconomyBanBanned
+}
+
+func GetPlayerBans(ctx context.Context, apiKey string, steamID64 uint64) (*PlayerBans, error) {
+	if ctx == nil {
+		return nil, ErrContextNil
+	}
+
+	err := validateSteamAPIKey(apiKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid api key: %w", err)
+	}
+
+	err = validateSteamID64(steamID64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid steamID64: %w", err)
+	}
+
+	var u *url.URL
+	u, err = url.Parse(playerBansURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse player bans URL: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("key", apiKey)
+	q.Set("steamids", strconv.FormatUint(steamID64, 10))
+	u.RawQuery = q.Encode()
+
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	err = applyHeaders(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply headers: %w", err)
+	}
+
+	var resp *http.Response
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	res := &playerBansResponse{}
+	err = json.NewDecoder(resp.Body).Decode(res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(res.Players) == 0 {
+		return nil, fmt.Errorf("no ban data found for steamID64: %d", steamID64)
+	}
+
+	player := res.Players[0]
+
+	var id uint64
+	id, err = strconv.ParseUint(player.SteamID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse steamID: %w", err)
+	}
+
+	return &PlayerBans{
+		SteamID64:        id,
+		CommunityBanned:  player.CommunityBanned,
+		VACBanned:        player.VACBanned,
+		NumberOfVACBans:  player.NumberOfVACBans,
+		DaysSinceLastBan: player.DaysSinceLastBan,
+		NumberOfGameBans: player.NumberOfGameBans,
+		EconomyBan:       player.EconomyBan,
+	}, nil
+}
+
+const playerBansURL = "https://api.steampowered.com/ISteamUser/GetPlayerBans/v1/"
+
+type playerBansResponse struct {
+	Players []struct {
+		SteamID          string `json:"SteamId"`
+		CommunityBanned  bool   `json:"CommunityBanned"`
+		VACBanned        bool   `json:"VACBanned"`
+		NumberOfVACBans  int    `json:"NumberOfVACBans"`
+		DaysSinceLastBan int    `json:"DaysSinceLastBan"`
+		NumberOfGameBans int    `json:"NumberOfGameBans"`
+		EconomyBan       string `json:"EconomyBan"`
+	} `json:"players"`
+}
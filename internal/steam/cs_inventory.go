@@ -3,14 +3,22 @@ package steam
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/devusSs/dropawp/internal/archive"
 )
 
 type CSInventory struct {
 	AllItems                   []CSInventoryItem `json:"all_items"`
 	MarketableItems            []CSInventoryItem `json:"marketable_items"`
 	MarketableAndTradableItems []CSInventoryItem `json:"marketable_and_tradable_items"`
+	TradeHeldItems             []CSInventoryItem `json:"trade_held_items"`
 }
 
 func (i *CSInventory) String() string {
@@ -18,20 +26,30 @@ func (i *CSInventory) String() string {
 }
 
 type CSInventoryItem struct {
-	IconURL           string `json:"icon_url"`
-	ActionInspectLink string `json:"inspect_url"`
-	Name              string `json:"name"`
-	NameColor         string `json:"name_color"`
-	MarketName        string `json:"market_name"`
-	MarketHashName    string `json:"market_hash_name"`
-	MarketInspectLink string `json:"market_inspect_link"`
-	Marketable        bool   `json:"marketable"`
-	Tradable          bool   `json:"tradable"`
+	IconURL           string    `json:"icon_url"`
+	ActionInspectLink string    `json:"inspect_url"`
+	Name              string    `json:"name"`
+	NameColor         string    `json:"name_color"`
+	MarketName        string    `json:"market_name"`
+	MarketHashName    string    `json:"market_hash_name"`
+	MarketInspectLink string    `json:"market_inspect_link"`
+	Marketable        bool      `json:"marketable"`
+	Tradable          bool      `json:"tradable"`
+	Type              string    `json:"type"`
+	Weapon            string    `json:"weapon"`
+	Quality           string    `json:"quality"`
+	Rarity            string    `json:"rarity"`
+	Exterior          string    `json:"exterior"`
+	Collection        string    `json:"collection"`
+	StatTrak          bool      `json:"stattrak"`
+	Souvenir          bool      `json:"souvenir"`
+	TradableAfter     time.Time `json:"tradable_after"`
+	AssetIDs          []string  `json:"asset_ids"`
 }
 
 func (i CSInventoryItem) String() string {
 	return fmt.Sprintf(
-		"CSInventoryItem{IconURL: %s, ActionInspectLink: %s, Name: %s, NameColor: %s, MarketName: %s, MarketHashName: %s, MarketInspectLink: %s, Marketable: %t, Tradable: %t}",
+		"CSInventoryItem{IconURL: %s, ActionInspectLink: %s, Name: %s, NameColor: %s, MarketName: %s, MarketHashName: %s, MarketInspectLink: %s, Marketable: %t, Tradable: %t, Type: %s, Weapon: %s, Quality: %s, Rarity: %s, Exterior: %s, Collection: %s, StatTrak: %t, Souvenir: %t, TradableAfter: %s, AssetIDs: %v}",
 		i.IconURL,
 		i.ActionInspectLink,
 		i.Name,
@@ -41,10 +59,46 @@ func (i CSInventoryItem) String() string {
 		i.MarketInspectLink,
 		i.Marketable,
 		i.Tradable,
+		i.Type,
+		i.Weapon,
+		i.Quality,
+		i.Rarity,
+		i.Exterior,
+		i.Collection,
+		i.StatTrak,
+		i.Souvenir,
+		i.TradableAfter.Format(time.RFC3339),
+		i.AssetIDs,
 	)
 }
 
+// App identifies an inventory by Steam app id and context id.
+type App struct {
+	ID        int    `json:"id"`
+	ContextID string `json:"context_id"`
+}
+
+func (a App) String() string {
+	return fmt.Sprintf("App{ID: %d, ContextID: %s}", a.ID, a.ContextID)
+}
+
+// Known apps with tradable items.
+//
+//nolint:mnd // Steam app ids.
+var (
+	AppCS2   = App{ID: 730, ContextID: "2"}
+	AppDota2 = App{ID: 570, ContextID: "2"}
+	AppTF2   = App{ID: 440, ContextID: "2"}
+	AppRust  = App{ID: 252490, ContextID: "2"}
+)
+
 func GetCSInventory(ctx context.Context, steamID64 uint64) (*CSInventory, error) {
+	return GetInventory(ctx, steamID64, AppCS2)
+}
+
+// GetInventory fetches the inventory of any Steam app and context.
+// The CS specific item fields are only populated for CS2 items.
+func GetInventory(ctx context.Context, steamID64 uint64, app App) (*CSInventory, error) {
 	if ctx == nil {
 		return nil, ErrContextNil
 	}
@@ -54,7 +108,11 @@ func GetCSInventory(ctx context.Context, steamID64 uint64) (*CSInventory, error)
 		return nil, fmt.Errorf("invalid steamID64: %w", err)
 	}
 
-	inventoryURL := fmt.Sprintf(inventoryURLFormat, steamID64)
+	if app.ID <= 0 || app.ContextID == "" {
+		return nil, fmt.Errorf("invalid app: %s", app)
+	}
+
+	inventoryURL := fmt.Sprintf(inventoryURLFormat, steamID64, app.ID, url.PathEscape(app.ContextID))
 
 	var req *http.Request
 	req, err = http.NewRequestWithContext(ctx, http.MethodGet, inventoryURL, nil)
@@ -75,11 +133,24 @@ func GetCSInventory(ctx context.Context, steamID64 uint64) (*CSInventory, error)
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, inventoryStatusError(resp.StatusCode)
 	}
 
+	var body []byte
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	archive.Record(ctx, archive.KindInventory, strconv.FormatUint(steamID64, 10), body)
+
+	return ParseInventory(body)
+}
+
+// ParseInventory parses a raw community inventory response, e.g. from an archived run.
+func ParseInventory(body []byte) (*CSInventory, error) {
 	res := &csInventoryResponse{}
-	err = json.NewDecoder(resp.Body).Decode(res)
+	err := json.Unmarshal(body, res)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
@@ -91,7 +162,25 @@ func GetCSInventory(ctx context.Context, steamID64 uint64) (*CSInventory, error)
 	return res.toCSInventory(), nil
 }
 
-const inventoryURLFormat = "https://steamcommunity.com/inventory/%d/730/2"
+const inventoryURLFormat = "https://steamcommunity.com/inventory/%d/%d/%s"
+
+var (
+	// ErrInventoryForbidden is returned if the inventory is private or otherwise not accessible.
+	ErrInventoryForbidden = errors.New("inventory access forbidden")
+	// ErrInventoryRateLimited is returned if Steam rejected the request due to rate limiting.
+	ErrInventoryRateLimited = errors.New("inventory requests rate limited")
+)
+
+func inventoryStatusError(statusCode int) error {
+	switch statusCode {
+	case http.StatusForbidden:
+		return fmt.Errorf("%w: status code %d", ErrInventoryForbidden, statusCode)
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: status code %d", ErrInventoryRateLimited, statusCode)
+	default:
+		return fmt.Errorf("unexpected status code: %d", statusCode)
+	}
+}
 
 type csInventoryResponse struct {
 	Assets []struct {
@@ -129,23 +218,68 @@ type csInventoryResponse struct {
 			Link string `json:"link"`
 			Name string `json:"name"`
 		} `json:"market_actions,omitempty"`
-		Commodity                   int `json:"commodity"`
-		MarketTradableRestriction   int `json:"market_tradable_restriction"`
-		MarketMarketableRestriction int `json:"market_marketable_restriction"`
-		Marketable                  int `json:"marketable"`
-		Tags                        []struct {
-			Category              string `json:"category"`
-			InternalName          string `json:"internal_name"`
-			LocalizedCategoryName string `json:"localized_category_name"`
-			LocalizedTagName      string `json:"localized_tag_name"`
-			Color                 string `json:"color,omitempty"`
-		} `json:"tags"`
+		Commodity                   int              `json:"commodity"`
+		MarketTradableRestriction   int              `json:"market_tradable_restriction"`
+		MarketMarketableRestriction int              `json:"market_marketable_restriction"`
+		Marketable                  int              `json:"marketable"`
+		Tags                        []csInventoryTag `json:"tags"`
+		OwnerDescriptions           []struct {
+			Type  string `json:"type"`
+			Value string `json:"value"`
+			Color string `json:"color,omitempty"`
+		} `json:"owner_descriptions,omitempty"`
+		CacheExpiration string `json:"cache_expiration,omitempty"`
 	} `json:"descriptions"`
 	TotalInventoryCount int `json:"total_inventory_count"`
 	Success             int `json:"success"`
 	Rwgrsn              int `json:"rwgrsn"`
 }
 
+type csInventoryTag struct {
+	Category              string `json:"category"`
+	InternalName          string `json:"internal_name"`
+	LocalizedCategoryName string `json:"localized_category_name"`
+	LocalizedTagName      string `json:"localized_tag_name"`
+	Color                 string `json:"color,omitempty"`
+}
+
+const (
+	tagCategoryType     = "Type"
+	tagCategoryWeapon   = "Weapon"
+	tagCategoryQuality  = "Quality"
+	tagCategoryRarity   = "Rarity"
+	tagCategoryExterior = "Exterior"
+	tagCategoryItemSet  = "ItemSet"
+
+	tagQualityStatTrak        = "strange"
+	tagQualityUnusualStatTrak = "unusual_strange"
+	tagQualitySouvenir        = "tournament"
+)
+
+func (i *CSInventoryItem) applyTags(tags []csInventoryTag) {
+	for _, tag := range tags {
+		switch tag.Category {
+		case tagCategoryType:
+			i.Type = tag.LocalizedTagName
+		case tagCategoryWeapon:
+			i.Weapon = tag.LocalizedTagName
+		case tagCategoryQuality:
+			i.Quality = tag.LocalizedTagName
+			// ★ StatTrak™ knives and gloves have their own quality.
+			i.StatTrak = i.StatTrak ||
+				tag.InternalName == tagQualityStatTrak ||
+				tag.InternalName == tagQualityUnusualStatTrak
+			i.Souvenir = i.Souvenir || tag.InternalName == tagQualitySouvenir
+		case tagCategoryRarity:
+			i.Rarity = tag.LocalizedTagName
+		case tagCategoryExterior:
+			i.Exterior = tag.LocalizedTagName
+		case tagCategoryItemSet:
+			i.Collection = tag.LocalizedTagName
+		}
+	}
+}
+
 const iconURLBase = "https://community.fastly.steamstatic.com/economy/image/"
 
 func (r *csInventoryResponse) toCSInventory() *CSInventory {
@@ -153,6 +287,13 @@ func (r *csInventoryResponse) toCSInventory() *CSInventory {
 		AllItems:                   make([]CSInventoryItem, 0, len(r.Descriptions)),
 		MarketableItems:            make([]CSInventoryItem, 0),
 		MarketableAndTradableItems: make([]CSInventoryItem, 0),
+		TradeHeldItems:             make([]CSInventoryItem, 0),
+	}
+
+	assetIDs := make(map[string][]string, len(r.Descriptions))
+	for _, asset := range r.Assets {
+		key := asset.Classid + "_" + asset.Instanceid
+		assetIDs[key] = append(assetIDs[key], asset.Assetid)
 	}
 
 	for _, desc := range r.Descriptions {
@@ -164,8 +305,18 @@ func (r *csInventoryResponse) toCSInventory() *CSInventory {
 			MarketHashName: desc.MarketHashName,
 			Marketable:     desc.Marketable == 1,
 			Tradable:       desc.Tradable == 1,
+			AssetIDs:       assetIDs[desc.Classid+"_"+desc.Instanceid],
+		}
+
+		item.applyTags(desc.Tags)
+
+		ownerDescriptions := make([]string, 0, len(desc.OwnerDescriptions))
+		for _, ownerDesc := range desc.OwnerDescriptions {
+			ownerDescriptions = append(ownerDescriptions, ownerDesc.Value)
 		}
 
+		item.TradableAfter = parseTradableAfter(desc.CacheExpiration, ownerDescriptions)
+
 		for _, action := range desc.Actions {
 			if action.Name == "Inspect in Game..." {
 				item.ActionInspectLink = action.Link
@@ -189,6 +340,10 @@ func (r *csInventoryResponse) toCSInventory() *CSInventory {
 		if item.Marketable {
 			i.MarketableItems = append(i.MarketableItems, item)
 		}
+
+		if item.TradeHeld() {
+			i.TradeHeldItems = append(i.TradeHeldItems, item)
+		}
 	}
 
 	return i
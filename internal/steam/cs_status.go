@@ -6,20 +6,132 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
+	"strings"
 	"time"
 )
 
 type CSServerStatus struct {
-	Version   int       `json:"version"`
-	Timestamp time.Time `json:"timestamp"`
-	Sessions  string    `json:"sessions"`
-	Community string    `json:"community"`
+	Version     int                   `json:"version"`
+	Timestamp   time.Time             `json:"timestamp"`
+	Sessions    string                `json:"sessions"`
+	Community   string                `json:"community"`
+	Services    map[string]string     `json:"services"`
+	Matchmaking Matchmaking           `json:"matchmaking"`
+	Datacenters map[string]Datacenter `json:"datacenters"`
+	Latency     time.Duration         `json:"latency"`
 }
 
 func (s *CSServerStatus) String() string {
 	return fmt.Sprintf("%+v", *s)
 }
 
+type Matchmaking struct {
+	Scheduler        string `json:"scheduler"`
+	OnlineServers    int    `json:"online_servers"`
+	OnlinePlayers    int    `json:"online_players"`
+	SearchingPlayers int    `json:"searching_players"`
+	SearchSecondsAvg int    `json:"search_seconds_avg"`
+}
+
+func (m Matchmaking) String() string {
+	return fmt.Sprintf(
+		"Matchmaking{Scheduler: %s, OnlineServers: %d, OnlinePlayers: %d, SearchingPlayers: %d, SearchSecondsAvg: %d}",
+		m.Scheduler,
+		m.OnlineServers,
+		m.OnlinePlayers,
+		m.SearchingPlayers,
+		m.SearchSecondsAvg,
+	)
+}
+
+type Datacenter struct {
+	Capacity string `json:"capacity"`
+	Load     string `json:"load"`
+}
+
+func (d Datacenter) String() string {
+	return fmt.Sprintf("Datacenter{Capacity: %s, Load: %s}", d.Capacity, d.Load)
+}
+
+// Service names as used in the service requirements.
+const (
+	ServiceSessionsLogon        = "SessionsLogon"
+	ServiceSteamCommunity       = "SteamCommunity"
+	ServiceIEconItems           = "IEconItems"
+	ServiceLeaderboards         = "Leaderboards"
+	ServiceMatchmakingScheduler = "MatchmakingScheduler"
+)
+
+// Services returns the names of all services which can be required.
+func Services() []string {
+	return []string{
+		ServiceSessionsLogon,
+		ServiceSteamCommunity,
+		ServiceIEconItems,
+		ServiceLeaderboards,
+		ServiceMatchmakingScheduler,
+	}
+}
+
+// Service states in descending order of health.
+const (
+	ServiceStateNormal  = "normal"
+	ServiceStateDelayed = "delayed"
+	ServiceStateOffline = "offline"
+)
+
+const (
+	serviceLevelOffline = iota
+	serviceLevelDelayed
+	serviceLevelNormal
+)
+
+// serviceStateLevel returns the health of a state, unknown states are treated like delayed.
+func serviceStateLevel(state string) int {
+	switch state {
+	case ServiceStateNormal:
+		return serviceLevelNormal
+	case ServiceStateOffline, "":
+		return serviceLevelOffline
+	default:
+		return serviceLevelDelayed
+	}
+}
+
+// ValidServiceState reports whether the state can be used as a minimum state.
+func ValidServiceState(state string) bool {
+	return state == ServiceStateNormal || state == ServiceStateDelayed || state == ServiceStateOffline
+}
+
+// CheckServices returns an error if any service is below its required minimum state.
+// The requirements map service names to minimum states.
+func (s *CSServerStatus) CheckServices(requirements map[string]string) error {
+	var issues []string
+
+	for service, minimum := range requirements {
+		if !ValidServiceState(minimum) {
+			return fmt.Errorf("invalid minimum state %s for service %s", minimum, service)
+		}
+
+		state, ok := s.Services[service]
+		if !ok {
+			return fmt.Errorf("unknown service: %s", service)
+		}
+
+		if serviceStateLevel(state) < serviceStateLevel(minimum) {
+			issues = append(issues, fmt.Sprintf("%s: %s (required %s)", service, state, minimum))
+		}
+	}
+
+	if len(issues) > 0 {
+		sort.Strings(issues)
+		return fmt.Errorf("required Steam services have issues: %s", strings.Join(issues, ", "))
+	}
+
+	return nil
+}
+
 func GetCSServerStatus(ctx context.Context, apiKey string) (*CSServerStatus, error) {
 	if ctx == nil {
 		return nil, ErrContextNil
@@ -51,6 +163,8 @@ func GetCSServerStatus(ctx context.Context, apiKey string) (*CSServerStatus, err
 		return nil, fmt.Errorf("failed to apply headers: %w", err)
 	}
 
+	start := time.Now()
+
 	var resp *http.Response
 	resp, err = http.DefaultClient.Do(req)
 	if err != nil {
@@ -58,6 +172,8 @@ func GetCSServerStatus(ctx context.Context, apiKey string) (*CSServerStatus, err
 	}
 	defer resp.Body.Close()
 
+	latency := time.Since(start)
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
@@ -68,7 +184,10 @@ func GetCSServerStatus(ctx context.Context, apiKey string) (*CSServerStatus, err
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return res.toCSServerStatus(), nil
+	status := res.toCSServerStatus()
+	status.Latency = latency
+
+	return status, nil
 }
 
 const csServerStatusURL = "https://api.steampowered.com/ICSGOServers_730/GetGameServersStatus/v1/"
@@ -86,127 +205,9 @@ type csServerStatusResponse struct {
 			IEconItems     string `json:"IEconItems"`
 			Leaderboards   string `json:"Leaderboards"`
 		} `json:"services"`
-		Datacenters struct {
-			Peru struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"Peru"`
-			EUGermany struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"EU Germany"`
-			EUAustria struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"EU Austria"`
-			EUPoland struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"EU Poland"`
-			HongKong struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"Hong Kong"`
-			EUSpain struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"EU Spain"`
-			Chile struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"Chile"`
-			USCalifornia struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"US California"`
-			USAtlanta struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"US Atlanta"`
-			ChinaGuangdong struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"China Guangdong"`
-			EUSweden struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"EU Sweden"`
-			Emirates struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"Emirates"`
-			USSeattle struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"US Seattle"`
-			SouthAfrica struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"South Africa"`
-			Brazil struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"Brazil"`
-			USVirginia struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"US Virginia"`
-			USChicago struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"US Chicago"`
-			Japan struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"Japan"`
-			ChinaPudong struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"China Pudong"`
-			EUFinland struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"EU Finland"`
-			IndiaMumbai struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"India Mumbai"`
-			IndiaChennai struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"India Chennai"`
-			USDallas struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"US Dallas"`
-			Argentina struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"Argentina"`
-			SouthKorea struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"South Korea"`
-			UnitedKingdom struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"United Kingdom"`
-			Singapore struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"Singapore"`
-			Australia struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"Australia"`
-			ChinaBeijing struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"China Beijing"`
-			ChinaChengdu struct {
-				Capacity string `json:"capacity"`
-				Load     string `json:"load"`
-			} `json:"China Chengdu"`
+		Datacenters map[string]struct {
+			Capacity string `json:"capacity"`
+			Load     string `json:"load"`
 		} `json:"datacenters"`
 		Matchmaking struct {
 			Scheduler        string `json:"scheduler"`
@@ -229,10 +230,33 @@ type csServerStatusResponse struct {
 }
 
 func (r *csServerStatusResponse) toCSServerStatus() *CSServerStatus {
+	datacenters := make(map[string]Datacenter, len(r.Result.Datacenters))
+	for name, dc := range r.Result.Datacenters {
+		datacenters[name] = Datacenter{
+			Capacity: dc.Capacity,
+			Load:     dc.Load,
+		}
+	}
+
 	return &CSServerStatus{
 		Version:   r.Result.App.Version,
 		Timestamp: time.Unix(int64(r.Result.App.Timestamp), 0),
 		Sessions:  r.Result.Services.SessionsLogon,
 		Community: r.Result.Services.SteamCommunity,
+		Services: map[string]string{
+			ServiceSessionsLogon:        r.Result.Services.SessionsLogon,
+			ServiceSteamCommunity:       r.Result.Services.SteamCommunity,
+			ServiceIEconItems:           r.Result.Services.IEconItems,
+			ServiceLeaderboards:         r.Result.Services.Leaderboards,
+			ServiceMatchmakingScheduler: r.Result.Matchmaking.Scheduler,
+		},
+		Matchmaking: Matchmaking{
+			Scheduler:        r.Result.Matchmaking.Scheduler,
+			OnlineServers:    r.Result.Matchmaking.OnlineServers,
+			OnlinePlayers:    r.Result.Matchmaking.OnlinePlayers,
+			SearchingPlayers: r.Result.Matchmaking.SearchingPlayers,
+			SearchSecondsAvg: r.Result.Matchmaking.SearchSecondsAvg,
+		},
+		Datacenters: datacenters,
 	}
 }
@@ -0,0 +1,184 @@
+package steam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/devusSs/dropawp/internal/archive"
+)
+
+// GetInventoryWithAPIKey fetches an inventory via the Steam Web API IEconService instead of the community endpoint.
+// It is not subject to the community rate limits and returns the same items as GetInventory.
+func GetInventoryWithAPIKey(ctx context.Context, apiKey string, steamID64 uint64, app App) (*CSInventory, error) {
+	if ctx == nil {
+		return nil, ErrContextNil
+	}
+
+	err := validateSteamAPIKey(apiKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid api key: %w", err)
+	}
+
+	err = validateSteamID64(steamID64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid steamID64: %w", err)
+	}
+
+	if app.ID <= 0 || app.ContextID == "" {
+		return nil, fmt.Errorf("invalid app: %s", app)
+	}
+
+	var pages [][]byte
+	startAssetID := ""
+
+	for {
+		var body []byte
+		body, err = getEconInventoryPage(ctx, apiKey, steamID64, app, startAssetID)
+		if err != nil {
+			return nil, err
+		}
+
+		archive.Record(ctx, archive.KindInventoryAPI, strconv.FormatUint(steamID64, 10), body)
+		pages = append(pages, body)
+
+		var page *econInventoryResponse
+		page, err = parseEconInventoryPage(body)
+		if err != nil {
+			return nil, err
+		}
+
+		if !page.Response.hasMoreItems() || page.Response.LastAssetID == "" ||
+			page.Response.LastAssetID == startAssetID {
+			break
+		}
+
+		startAssetID = page.Response.LastAssetID
+	}
+
+	return ParseInventoryPages(pages)
+}
+
+// ParseInventoryPages parses raw IEconService inventory pages, e.g. from an archived run.
+func ParseInventoryPages(pages [][]byte) (*CSInventory, error) {
+	res := &csInventoryResponse{Success: 1}
+	seenDescriptions := make(map[string]bool)
+
+	for _, body := range pages {
+		page, err := parseEconInventoryPage(body)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Assets = append(res.Assets, page.Response.Assets...)
+		res.TotalInventoryCount = page.Response.TotalInventoryCount
+
+		// Descriptions are repeated on every page which contains an asset of them.
+		for _, desc := range page.Response.Descriptions {
+			key := desc.Classid + "_" + desc.Instanceid
+			if seenDescriptions[key] {
+				continue
+			}
+
+			seenDescriptions[key] = true
+			res.Descriptions = append(res.Descriptions, desc)
+		}
+	}
+
+	return res.toCSInventory(), nil
+}
+
+func parseEconInventoryPage(body []byte) (*econInventoryResponse, error) {
+	res := &econInventoryResponse{}
+	err := json.Unmarshal(body, res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return res, nil
+}
+
+const (
+	econInventoryURL      = "https://api.steampowered.com/IEconService/GetInventoryItemsWithDescriptions/v1/"
+	econInventoryPageSize = 2000
+)
+
+type econInventoryResponse struct {
+	Response econInventoryResponseBody `json:"response"`
+}
+
+type econInventoryResponseBody struct {
+	csInventoryResponse
+
+	MoreItems   json.RawMessage `json:"more_items"`
+	LastAssetID string          `json:"last_assetid"`
+}
+
+// hasMoreItems handles more_items being returned as boolean or number.
+func (r *econInventoryResponseBody) hasMoreItems() bool {
+	switch string(r.MoreItems) {
+	case "true", "1":
+		return true
+	default:
+		return false
+	}
+}
+
+func getEconInventoryPage(
+	ctx context.Context,
+	apiKey string,
+	steamID64 uint64,
+	app App,
+	startAssetID string,
+) ([]byte, error) {
+	u, err := url.Parse(econInventoryURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse inventory URL: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("key", apiKey)
+	q.Set("steamid", strconv.FormatUint(steamID64, 10))
+	q.Set("appid", strconv.Itoa(app.ID))
+	q.Set("contextid", app.ContextID)
+	q.Set("get_descriptions", "true")
+	q.Set("count", strconv.Itoa(econInventoryPageSize))
+	if startAssetID != "" {
+		q.Set("start_assetid", startAssetID)
+	}
+	u.RawQuery = q.Encode()
+
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	err = applyHeaders(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply headers: %w", err)
+	}
+
+	var resp *http.Response
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, inventoryStatusError(resp.StatusCode)
+	}
+
+	var body []byte
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,170 @@
+package steam
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/devusSs/dropawp/internal/archive"
+)
+
+type MarketPriceOverview struct {
+	LowestPrice int `json:"lowest_price"`
+	MedianPrice int `json:"median_price"`
+	Volume      int `json:"volume"`
+}
+
+func (o *MarketPriceOverview) String() string {
+	return fmt.Sprintf("%+v", *o)
+}
+
+// GetMarketPriceOverview returns the Steam Community Market prices of an item in USD cents.
+func GetMarketPriceOverview(ctx context.Context, appID int, marketHashName string) (*MarketPriceOverview, error) {
+	if ctx == nil {
+		return nil, ErrContextNil
+	}
+
+	if appID <= 0 {
+		return nil, errors.New("appID must be positive")
+	}
+
+	if marketHashName == "" {
+		return nil, errors.New("marketHashName cannot be empty")
+	}
+
+	u, err := url.Parse(marketPriceOverviewURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse market price overview URL: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("appid", strconv.Itoa(appID))
+	q.Set("currency", marketCurrencyUSD)
+	q.Set("market_hash_name", marketHashName)
+	u.RawQuery = q.Encode()
+
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	err = applyHeaders(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply headers: %w", err)
+	}
+
+	var resp *http.Response
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("%w: status code %d", ErrMarketRateLimited, resp.StatusCode)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var body []byte
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	archive.Record(ctx, archive.KindSteamMarketPrice, marketHashName, body)
+
+	return ParseMarketPriceOverview(body)
+}
+
+// ParseMarketPriceOverview parses a raw price overview response, e.g. from an archived run.
+func ParseMarketPriceOverview(body []byte) (*MarketPriceOverview, error) {
+	res := &marketPriceOverviewResponse{}
+	err := json.Unmarshal(body, res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if !res.Success {
+		return nil, errors.New("no market data found for the given market hash name")
+	}
+
+	overview := &MarketPriceOverview{}
+
+	if res.LowestPrice != "" {
+		overview.LowestPrice, err = parseMarketPrice(res.LowestPrice)
+		if err != nil {
+			return nil, fmt.Errorf("invalid lowest price: %w", err)
+		}
+	}
+
+	if res.MedianPrice != "" {
+		overview.MedianPrice, err = parseMarketPrice(res.MedianPrice)
+		if err != nil {
+			return nil, fmt.Errorf("invalid median price: %w", err)
+		}
+	}
+
+	if res.Volume != "" {
+		overview.Volume, err = strconv.Atoi(strings.ReplaceAll(res.Volume, ",", ""))
+		if err != nil {
+			return nil, fmt.Errorf("invalid volume: %w", err)
+		}
+	}
+
+	return overview, nil
+}
+
+// ErrMarketRateLimited is returned if Steam rejected a market request due to rate limiting.
+var ErrMarketRateLimited = errors.New("market requests rate limited")
+
+const (
+	marketPriceOverviewURL = "https://steamcommunity.com/market/priceoverview/"
+	marketCurrencyUSD      = "1"
+)
+
+type marketPriceOverviewResponse struct {
+	Success     bool   `json:"success"`
+	LowestPrice string `json:"lowest_price"`
+	Volume      string `json:"volume"`
+	MedianPrice string `json:"median_price"`
+}
+
+// parseMarketPrice parses a USD price like "$1,234.56" into cents.
+func parseMarketPrice(s string) (int, error) {
+	s = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s), "$"))
+	s = strings.TrimSuffix(s, " USD")
+	s = strings.ReplaceAll(s, ",", "")
+
+	dollars, cents, found := strings.Cut(s, ".")
+
+	d, err := strconv.Atoi(dollars)
+	if err != nil {
+		return 0, fmt.Errorf("invalid price %q: %w", s, err)
+	}
+
+	c := 0
+	if found {
+		if len(cents) == 1 {
+			cents += "0"
+		}
+
+		c, err = strconv.Atoi(cents)
+		if err != nil || len(cents) != 2 {
+			return 0, fmt.Errorf("invalid price %q", s)
+		}
+	}
+
+	const centsPerDollar = 100
+
+	return d*centsPerDollar + c, nil
+}
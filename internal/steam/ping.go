@@ -0,0 +1,45 @@
+package steam
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// PingCommunity checks whether the Steam community, which serves inventories, is reachable
+// and returns the response latency.
+func PingCommunity(ctx context.Context) (time.Duration, error) {
+	if ctx == nil {
+		return 0, ErrContextNil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, communityURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	err = applyHeaders(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to apply headers: %w", err)
+	}
+
+	start := time.Now()
+
+	var resp *http.Response
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	latency := time.Since(start)
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return latency, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return latency, nil
+}
+
+const communityURL = "https://steamcommunity.com/"
@@ -0,0 +1,174 @@
+package steam
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// steamID64Base is the SteamID64 of account ID 0 in the public universe.
+const steamID64Base = 76561197960265728
+
+var (
+	steamID2Regex     = regexp.MustCompile(`^STEAM_[0-5]:([01]):(\d+)$`)
+	steamID3Regex     = regexp.MustCompile(`^\[U:1:(\d+)\]$`)
+	profilesURLRegex  = regexp.MustCompile(`^(?:https?://)?(?:www\.)?steamcommunity\.com/profiles/(\d+)/?`)
+	vanityURLRegex    = regexp.MustCompile(`^(?:https?://)?(?:www\.)?steamcommunity\.com/id/([^/?#]+)/?`)
+	vanityNameRegex   = regexp.MustCompile(`^[A-Za-z0-9_-]{2,32}$`)
+	numericInputRegex = regexp.MustCompile(`^\d+$`)
+)
+
+// ParseSteamID parses a SteamID64, SteamID2 (STEAM_0:1:1234), SteamID3 ([U:1:1234])
+// or a steamcommunity.com/profiles/<id> URL without network access.
+//
+// For steamcommunity.com/id/<vanity> URLs and plain vanity names the vanity name is returned,
+// which has to be resolved via ResolveVanityURL. Numeric input is always parsed as SteamID64,
+// it is never treated as vanity name.
+func ParseSteamID(input string) (uint64, string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return 0, "", errors.New("input cannot be empty")
+	}
+
+	if numericInputRegex.MatchString(input) {
+		return parseAndValidateSteamID64(input)
+	}
+
+	if m := profilesURLRegex.FindStringSubmatch(input); m != nil {
+		return parseAndValidateSteamID64(m[1])
+	}
+
+	if m := steamID2Regex.FindStringSubmatch(input); m != nil {
+		y, err := strconv.ParseUint(m[1], 10, 64)
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid steamID2: %w", err)
+		}
+
+		var z uint64
+		z, err = strconv.ParseUint(m[2], 10, 32)
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid steamID2: %w", err)
+		}
+
+		return validatedSteamID64(steamID64Base + z*2 + y)
+	}
+
+	if m := steamID3Regex.FindStringSubmatch(input); m != nil {
+		w, err := strconv.ParseUint(m[1], 10, 32)
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid steamID3: %w", err)
+		}
+
+		return validatedSteamID64(steamID64Base + w)
+	}
+
+	if m := vanityURLRegex.FindStringSubmatch(input); m != nil {
+		return 0, m[1], nil
+	}
+
+	if vanityNameRegex.MatchString(input) {
+		return 0, input, nil
+	}
+
+	return 0, "", fmt.Errorf("unrecognized steam id or profile url: %s", input)
+}
+
+func parseAndValidateSteamID64(s string) (uint64, string, error) {
+	id, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid steamID64: %w", err)
+	}
+
+	return validatedSteamID64(id)
+}
+
+func validatedSteamID64(id uint64) (uint64, string, error) {
+	err := validateSteamID64(id)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return id, "", nil
+}
+
+// ResolveVanityURL resolves the vanity name of a custom profile URL to a SteamID64.
+func ResolveVanityURL(ctx context.Context, apiKey string, vanity string) (uint64, error) {
+	if ctx == nil {
+		return 0, ErrContextNil
+	}
+
+	err := validateSteamAPIKey(apiKey)
+	if err != nil {
+		return 0, fmt.Errorf("invalid api key: %w", err)
+	}
+
+	if vanity == "" {
+		return 0, errors.New("vanity name cannot be empty")
+	}
+
+	var u *url.URL
+	u, err = url.Parse(resolveVanityURL)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse resolve vanity URL: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("key", apiKey)
+	q.Set("vanityurl", vanity)
+	u.RawQuery = q.Encode()
+
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	err = applyHeaders(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to apply headers: %w", err)
+	}
+
+	var resp *http.Response
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	res := &resolveVanityURLResponse{}
+	err = json.NewDecoder(resp.Body).Decode(res)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if res.Response.Success != 1 {
+		return 0, fmt.Errorf("no steam profile found for vanity name %s: %s", vanity, res.Response.Message)
+	}
+
+	id, _, err := parseAndValidateSteamID64(res.Response.SteamID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse resolved steamID64: %w", err)
+	}
+
+	return id, nil
+}
+
+const resolveVanityURL = "https://api.steampowered.com/ISteamUser/ResolveVanityURL/v1/"
+
+type resolveVanityURLResponse struct {
+	Response struct {
+		SteamID string `json:"steamid"`
+		Success int    `json:"success"`
+		Message string `json:"message"`
+	} `json:"response"`
+}
@@ -0,0 +1,51 @@
+package steam
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Steam describes trade cooldowns in the owner descriptions of an item, either as
+// "Tradable/Marketable After Jan 15, 2025 (7:00:00) GMT" or with a unix timestamp
+// like "Tradable After [date]1736924400[/date]".
+var (
+	tradableAfterDateRegex      = regexp.MustCompile(`(?i)tradable(?:/marketable)? after (.+?) GMT`)
+	tradableAfterTimestampRegex = regexp.MustCompile(`(?i)tradable(?:/marketable)? after \[date\](\d+)\[/date\]`)
+)
+
+const tradableAfterLayout = "Jan 2, 2006 (15:04:05)"
+
+// parseTradableAfter returns the end of a trade cooldown or the zero time if none was found.
+func parseTradableAfter(cacheExpiration string, ownerDescriptions []string) time.Time {
+	if cacheExpiration != "" {
+		t, err := time.Parse(time.RFC3339, cacheExpiration)
+		if err == nil {
+			return t
+		}
+	}
+
+	for _, desc := range ownerDescriptions {
+		if m := tradableAfterTimestampRegex.FindStringSubmatch(desc); m != nil {
+			unix, err := strconv.ParseInt(m[1], 10, 64)
+			if err == nil {
+				return time.Unix(unix, 0).UTC()
+			}
+		}
+
+		if m := tradableAfterDateRegex.FindStringSubmatch(desc); m != nil {
+			t, err := time.Parse(tradableAfterLayout, strings.TrimSpace(m[1]))
+			if err == nil {
+				return t
+			}
+		}
+	}
+
+	return time.Time{}
+}
+
+// TradeHeld reports whether the item is currently restricted by a trade cooldown.
+func (i CSInventoryItem) TradeHeld() bool {
+	return (!i.Tradable || !i.Marketable) && i.TradableAfter.After(time.Now())
+}
@@ -0,0 +1,62 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+var ErrInvalidAPIKey = errors.New("api key was rejected by Steam")
+
+// ValidateAPIKey checks the API key with a cheap Steam Web API call.
+func ValidateAPIKey(ctx context.Context, apiKey string) error {
+	if ctx == nil {
+		return ErrContextNil
+	}
+
+	err := validateSteamAPIKey(apiKey)
+	if err != nil {
+		return fmt.Errorf("invalid api key: %w", err)
+	}
+
+	var u *url.URL
+	u, err = url.Parse(supportedAPIListURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse supported API list URL: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("key", apiKey)
+	u.RawQuery = q.Encode()
+
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	err = applyHeaders(req)
+	if err != nil {
+		return fmt.Errorf("failed to apply headers: %w", err)
+	}
+
+	var resp *http.Response
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrInvalidAPIKey
+	default:
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+}
+
+const supportedAPIListURL = "https://api.steampowered.com/ISteamWebAPIUtil/GetSupportedAPIList/v1/"
@@ -6,36 +6,145 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 )
 
 type Inventory struct {
-	Timestamp time.Time       `json:"timestamp"`
-	Items     []InventoryItem `json:"items"`
+	Timestamp    time.Time       `json:"timestamp"`
+	Items        []InventoryItem `json:"items"`
+	Accounts     []Holdings      `json:"accounts"`
+	Total        Holdings        `json:"total"`
+	ExchangeRate *ExchangeRate   `json:"exchange_rate,omitempty"`
+	FeeSchedule  *FeeSchedule    `json:"fee_schedule,omitempty"`
+	Settings     *Settings       `json:"settings,omitempty"`
+	Assets       []Asset         `json:"assets,omitempty"`
 }
 
 func (i *Inventory) String() string {
 	return fmt.Sprintf("%+v", *i)
 }
 
+// Holdings sums up the items of an account or of the whole snapshot.
+// Values are given in the item currency.
+type Holdings struct {
+	Account          string `json:"account"`
+	SteamID64        uint64 `json:"steam_id_64"`
+	Amount           int    `json:"amount"`
+	Value            int    `json:"value"`
+	Fees             int    `json:"fees"`
+	LiquidationValue int    `json:"liquidation_value"`
+	TradeBanned      bool   `json:"trade_banned"`
+}
+
+func (h *Holdings) String() string {
+	return fmt.Sprintf("%+v", *h)
+}
+
+func (h *Holdings) Add(item InventoryItem) {
+	h.Amount += item.Amount
+	h.Value += item.Price * item.Amount
+	h.Fees += item.Fee * item.Amount
+	h.LiquidationValue += item.LiquidationPrice * item.Amount
+}
+
+// Asset is a single inventory item of a snapshot. Assets are compared between runs to detect new items.
+// Price is zero if no price was found.
+type Asset struct {
+	Account        string `json:"account"`
+	AssetID        string `json:"asset_id"`
+	MarketHashName string `json:"market_hash_name"`
+	Type           string `json:"type"`
+	Rarity         string `json:"rarity"`
+	Price          int    `json:"price"`
+}
+
+func (a Asset) String() string {
+	return fmt.Sprintf(
+		"Asset{Account: %s, AssetID: %s, MarketHashName: %s, Type: %s, Rarity: %s, Price: %d}",
+		a.Account,
+		a.AssetID,
+		a.MarketHashName,
+		a.Type,
+		a.Rarity,
+		a.Price,
+	)
+}
+
+// ExchangeRate is the rate used to convert item prices into the display currency of a snapshot.
+type ExchangeRate struct {
+	From     string    `json:"from"`
+	To       string    `json:"to"`
+	Rate     float64   `json:"rate"`
+	Date     time.Time `json:"date"`
+	Provider string    `json:"provider"`
+}
+
+func (r *ExchangeRate) String() string {
+	return fmt.Sprintf("%+v", *r)
+}
+
+// FeeSchedule is the marketplace seller fee used to calculate the fees of a snapshot.
+type FeeSchedule struct {
+	Provider string  `json:"provider"`
+	Percent  float64 `json:"percent"`
+	Minimum  int     `json:"minimum"`
+}
+
+func (f *FeeSchedule) String() string {
+	return fmt.Sprintf("%+v", *f)
+}
+
+// Settings are the config values which decided the items and liquidation values of a snapshot.
+type Settings struct {
+	TradeBanAction            string `json:"trade_ban_action"`
+	SkipFilterUntradableItems bool   `json:"skip_filter_untradable_items"`
+	IncludeTradeHeldItems     bool   `json:"include_trade_held_items"`
+}
+
+func (s *Settings) String() string {
+	return fmt.Sprintf("%+v", *s)
+}
+
 type InventoryItem struct {
-	IconURL           string `json:"icon_url"`
-	ActionInspectLink string `json:"inspect_url"`
-	Name              string `json:"name"`
-	NameColor         string `json:"name_color"`
-	MarketName        string `json:"market_name"`
-	MarketHashName    string `json:"market_hash_name"`
-	MarketInspectLink string `json:"market_inspect_link"`
-	Marketable        bool   `json:"marketable"`
-	Tradable          bool   `json:"tradable"`
-	Amount            int    `json:"amount"`
-	Price             int    `json:"price"`
-	Currency          string `json:"currency"`
+	Account           string    `json:"account"`
+	IconURL           string    `json:"icon_url"`
+	ActionInspectLink string    `json:"inspect_url"`
+	Name              string    `json:"name"`
+	NameColor         string    `json:"name_color"`
+	MarketName        string    `json:"market_name"`
+	MarketHashName    string    `json:"market_hash_name"`
+	MarketInspectLink string    `json:"market_inspect_link"`
+	Marketable        bool      `json:"marketable"`
+	Tradable          bool      `json:"tradable"`
+	Type              string    `json:"type"`
+	Weapon            string    `json:"weapon"`
+	Quality           string    `json:"quality"`
+	Rarity            string    `json:"rarity"`
+	Exterior          string    `json:"exterior"`
+	Collection        string    `json:"collection"`
+	StatTrak          bool      `json:"stattrak"`
+	Souvenir          bool      `json:"souvenir"`
+	TradableAfter     time.Time `json:"tradable_after"`
+	Amount            int       `json:"amount"`
+	Price             int       `json:"price"`
+	LiquidationPrice  int       `json:"liquidation_price"`
+	Fee               int       `json:"fee"`
+	Currency          string    `json:"currency"`
+
+	// Metadata of additional items, see the additional package.
+	Location      string    `json:"location,omitempty"`
+	PurchasePrice int       `json:"purchase_price,omitempty"`
+	PurchaseDate  time.Time `json:"purchase_date,omitzero"`
+	Notes         string    `json:"notes,omitempty"`
+	ManualPrice   bool      `json:"manual_price,omitempty"`
 }
 
 func (i InventoryItem) String() string {
 	return fmt.Sprintf(
-		"InventoryItem{IconURL: %s, ActionInspectLink: %s, Name: %s, NameColor: %s, MarketName: %s, MarketHashName: %s, MarketInspectLink: %s, Marketable: %t, Tradable: %t, Amount: %d, Price: %d, Currency: %s}",
+		"InventoryItem{Account: %s, IconURL: %s, ActionInspectLink: %s, Name: %s, NameColor: %s, MarketName: %s, MarketHashName: %s, MarketInspectLink: %s, Marketable: %t, Tradable: %t, Type: %s, Weapon: %s, Quality: %s, Rarity: %s, Exterior: %s, Collection: %s, StatTrak: %t, Souvenir: %t, TradableAfter: %s, Amount: %d, Price: %d, LiquidationPrice: %d, Fee: %d, Currency: %s, Location: %s, PurchasePrice: %d, PurchaseDate: %s, Notes: %s, ManualPrice: %t}",
+		i.Account,
 		i.IconURL,
 		i.ActionInspectLink,
 		i.Name,
@@ -45,29 +154,60 @@ func (i InventoryItem) String() string {
 		i.MarketInspectLink,
 		i.Marketable,
 		i.Tradable,
+		i.Type,
+		i.Weapon,
+		i.Quality,
+		i.Rarity,
+		i.Exterior,
+		i.Collection,
+		i.StatTrak,
+		i.Souvenir,
+		i.TradableAfter.Format(time.RFC3339),
 		i.Amount,
 		i.Price,
+		i.LiquidationPrice,
+		i.Fee,
 		i.Currency,
+		i.Location,
+		i.PurchasePrice,
+		i.PurchaseDate.Format(time.RFC3339),
+		i.Notes,
+		i.ManualPrice,
 	)
 }
 
-func Write(projectName string, items []InventoryItem) error {
+func Write(projectName string, i *Inventory) error {
 	if projectName == "" {
 		return errors.New("project name cannot be empty")
 	}
 
-	if len(items) == 0 {
-		return errors.New("no items to write")
+	if i == nil {
+		return errors.New("inventory cannot be nil")
+	}
+
+	i.Timestamp = time.Now()
+
+	storageFilePath, err := Path(projectName)
+	if err != nil {
+		return fmt.Errorf("failed to get storage file path: %w", err)
 	}
 
-	i := &Inventory{
-		Timestamp: time.Now(),
-		Items:     items,
+	return WriteFile(storageFilePath, i)
+}
+
+// WriteFile writes a snapshot to the given path without updating its timestamp.
+func WriteFile(path string, i *Inventory) error {
+	if i == nil {
+		return errors.New("inventory cannot be nil")
+	}
+
+	if len(i.Items) == 0 {
+		return errors.New("no items to write")
 	}
 
-	storageFile, err := createStorageFile(projectName)
+	storageFile, err := os.Create(path)
 	if err != nil {
-		return fmt.Errorf("failed to create storage file: %w", err)
+		return fmt.Errorf("failed to create storage file %s: %w", path, err)
 	}
 	defer storageFile.Close()
 
@@ -79,22 +219,112 @@ func Write(projectName string, items []InventoryItem) error {
 	return nil
 }
 
+// Read reads a snapshot written by Write or WriteFile.
+func Read(path string) (*Inventory, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open storage file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	i := &Inventory{}
+	err = json.NewDecoder(f).Decode(i)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode storage file %s: %w", path, err)
+	}
+
+	return i, nil
+}
+
 var storageFileTimestamp = time.Now().Format("2006-01-02_15-04-05")
 
-func createStorageFile(projectName string) (*os.File, error) {
+// Path returns the path of the snapshot written by the current run.
+func Path(projectName string) (string, error) {
 	storageDir, err := setupStorageDir(projectName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to setup storage directory: %w", err)
+		return "", fmt.Errorf("failed to setup storage directory: %w", err)
+	}
+
+	return filepath.Join(storageDir, "storage_"+storageFileTimestamp+".json"), nil
+}
+
+// List returns the paths of all snapshots of a project, oldest first.
+// Reprocessed snapshots are not included.
+func List(projectName string) ([]string, error) {
+	storageDir, err := Dir(projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	paths, err = filepath.Glob(filepath.Join(storageDir, "storage_*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list storage files: %w", err)
 	}
 
-	storageFilePath := filepath.Join(storageDir, "storage_"+storageFileTimestamp+".json")
+	snapshots := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if strings.HasSuffix(path, ".reprocessed.json") {
+			continue
+		}
+
+		snapshots = append(snapshots, path)
+	}
+
+	// The timestamp format sorts chronologically.
+	sort.Strings(snapshots)
+
+	return snapshots, nil
+}
 
-	file, err := os.Create(storageFilePath)
+// Dir returns the storage directory of a project, creating it if necessary.
+func Dir(projectName string) (string, error) {
+	if projectName == "" {
+		return "", errors.New("project name cannot be empty")
+	}
+
+	storageDir, err := setupStorageDir(projectName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create storage file %s: %w", storageFilePath, err)
+		return "", fmt.Errorf("failed to setup storage directory: %w", err)
 	}
 
-	return file, nil
+	return storageDir, nil
+}
+
+// ResolvePath returns the path of a snapshot given as path or as file name within the storage directory of a project.
+func ResolvePath(projectName string, snapshot string) (string, error) {
+	if snapshot == "" {
+		return "", errors.New("snapshot cannot be empty")
+	}
+
+	_, err := os.Stat(snapshot)
+	if err == nil {
+		return snapshot, nil
+	}
+
+	storageDir, err := setupStorageDir(projectName)
+	if err != nil {
+		return "", fmt.Errorf("failed to setup storage directory: %w", err)
+	}
+
+	path := filepath.Join(storageDir, filepath.Base(snapshot))
+
+	_, err = os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("snapshot %s not found: %w", snapshot, err)
+	}
+
+	return path, nil
+}
+
+// RawArchivePath returns the path of the raw response archive stored next to a snapshot.
+func RawArchivePath(snapshotPath string) string {
+	return strings.TrimSuffix(snapshotPath, ".json") + ".raw.json.gz"
+}
+
+// ReprocessedPath returns the path a reprocessed snapshot is stored at if the original is kept.
+func ReprocessedPath(snapshotPath string) string {
+	return strings.TrimSuffix(snapshotPath, ".json") + ".reprocessed.json"
 }
 
 func setupStorageDir(projectName string) (string, error) {